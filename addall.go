@@ -0,0 +1,92 @@
+package wrmatch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RouteError is the error of a single route of Router.AddAll or
+// Pattern.AddAll, whose routes have no method.
+type RouteError struct {
+	Route Route
+	Err   error
+}
+
+func (e *RouteError) Error() string {
+	if e.Route.Method == "" {
+		return e.Route.Path + ": " + e.Err.Error()
+	}
+	return e.Route.Method + " " + e.Route.Path + ": " + e.Err.Error()
+}
+
+// Unwrap returns the registration error, e.g. a *ConflictError.
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+// RouteErrors lists the routes Router.AddAll or Pattern.AddAll failed to
+// register.
+type RouteErrors []*RouteError
+
+func (e RouteErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strconv.Itoa(len(e)) + " routes could not be registered: " + strings.Join(msgs, "; ")
+}
+
+// AddAll registers all routes, including their disabled state and name.
+// In contrast to Add, it does not panic on the first invalid or conflicting
+// route, but registers all other routes and returns a RouteErrors listing
+// every route which could not be registered or named.
+func (r *Router) AddAll(routes []Route) error {
+	var errs RouteErrors
+	for _, route := range routes {
+		err := r.AddE(route.Method, route.Path, route.Value)
+		if err == nil {
+			if route.Disabled {
+				r.Disable(route.Method, route.Path)
+			}
+			if route.Name != "" {
+				err = registrationError(func() { r.setName(route.Method, route.Path, route.Name) })
+			}
+		}
+		if err != nil {
+			errs = append(errs, &RouteError{route, err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// AddAll registers the values of all paths in lexical order of the paths.
+// In contrast to Add, it does not panic on the first invalid or conflicting
+// path, but registers all other paths and returns a RouteErrors listing
+// every path which could not be registered.
+func (r *Pattern) AddAll(values map[string]interface{}) error {
+	if r.concurrentWrites {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var errs RouteErrors
+	for _, path := range paths {
+		value := values[path]
+		if err := registrationError(func() { r.add(r.convertPath(path), value) }); err != nil {
+			errs = append(errs, &RouteError{Route{Path: path, Value: value}, err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
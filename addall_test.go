@@ -0,0 +1,70 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterAddAll(t *testing.T) {
+	router := New()
+	err := router.AddAll([]Route{
+		{Method: http.MethodGet, Path: "/user/:name", Value: "user", Name: "user"},
+		{Method: http.MethodGet, Path: "/user/:id/about", Value: "about"},
+		{Method: http.MethodGet, Path: "noSlash", Value: "invalid"},
+		{Method: http.MethodGet, Path: "/team", Value: "team", Disabled: true},
+		{Method: http.MethodGet, Path: "/team", Value: "team"},
+		{Method: http.MethodPost, Path: "/team", Value: "create", Name: "user"},
+		{Method: http.MethodGet, Path: "/blog", Value: "blog"},
+	})
+	require.Error(t, err)
+
+	errs, ok := err.(RouteErrors)
+	require.True(t, ok)
+	require.Len(t, errs, 4)
+	require.Equal(t, "/user/:id/about", errs[0].Route.Path)
+	_, ok = errs[0].Unwrap().(*ConflictError)
+	require.True(t, ok)
+	require.EqualError(t, errs[1], "GET noSlash: path must begin with '/' in path 'noSlash'")
+	require.Equal(t, "/team", errs[2].Route.Path)
+	require.Equal(t, http.MethodPost, errs[3].Route.Method)
+	require.Contains(t, err.Error(), "4 routes could not be registered: GET /user/:id/about: ")
+
+	require.Equal(t, []Route{
+		{http.MethodGet, "/blog", "blog", false, ""},
+		{http.MethodGet, "/team", "team", true, ""},
+		{http.MethodGet, "/user/:name", "user", false, "user"},
+		{http.MethodPost, "/team", "create", false, ""},
+	}, router.Routes())
+
+	require.NoError(t, New().AddAll([]Route{{Method: http.MethodGet, Path: "/", Value: "index"}}))
+}
+
+func TestPatternAddAll(t *testing.T) {
+	pattern := NewPattern()
+	err := pattern.AddAll(map[string]interface{}{
+		"/user/:name":     "user",
+		"/user/:id/about": "about",
+		"noSlash":         "invalid",
+		"/blog":           "blog",
+		"/team":           nil,
+	})
+	require.Error(t, err)
+
+	errs, ok := err.(RouteErrors)
+	require.True(t, ok)
+	require.Len(t, errs, 3)
+	require.EqualError(t, errs[0], "/team: value must not be nil")
+	_, ok = errs[1].Unwrap().(*ConflictError)
+	require.True(t, ok)
+	require.Equal(t, "/user/:name", errs[1].Route.Path)
+	require.EqualError(t, errs[2], "noSlash: path must begin with '/' in path 'noSlash'")
+
+	require.Equal(t, []PatternEntry{
+		{"/blog", "blog"},
+		{"/user/:id/about", "about"},
+	}, pattern.List())
+
+	require.NoError(t, NewPattern().AddAll(map[string]interface{}{"/": "index"}))
+}
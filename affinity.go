@@ -0,0 +1,19 @@
+package wrmatch
+
+import "hash/fnv"
+
+// AffinityKey hashes the values of the given url params of the match into a
+// key for sticky sessions, e.g. AffinityKey("tenant", "user"). The key is
+// stable across processes and releases, it only depends on the param values
+// and their order. Missing params hash like empty values.
+func (m *MatchResult) AffinityKey(params ...string) uint64 {
+	h := fnv.New64a()
+	for i, name := range params {
+		if i > 0 {
+			// separate the values, so that ("ab", "c") and ("a", "bc") differ
+			_, _ = h.Write([]byte{0})
+		}
+		_, _ = h.Write([]byte(m.Params.Param(name)))
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,27 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchResultAffinityKey(t *testing.T) {
+	router := New()
+	router.GET("/tenant/:tenant/user/:user/*path", "user")
+
+	res1 := router.MatchResult(http.MethodGet, "/tenant/a/user/b/x")
+	res2 := router.MatchResult(http.MethodGet, "/tenant/a/user/b/y")
+	res3 := router.MatchResult(http.MethodGet, "/tenant/a/user/c/x")
+	res4 := router.MatchResult(http.MethodGet, "/tenant/ab/user/c/x")
+
+	require.Equal(t, res1.AffinityKey("tenant", "user"), res2.AffinityKey("tenant", "user"))
+	require.NotEqual(t, res1.AffinityKey("tenant", "user"), res3.AffinityKey("tenant", "user"))
+	require.NotEqual(t, res1.AffinityKey("tenant", "user"), res1.AffinityKey("user", "tenant"))
+	require.Equal(t, res1.AffinityKey("tenant"), res3.AffinityKey("tenant"))
+	require.NotEqual(t, res3.AffinityKey("tenant", "user"), res4.AffinityKey("tenant", "user"))
+
+	// the key is stable, it is the FNV-1a hash of "a"
+	require.Equal(t, uint64(0xaf63dc4c8601ec8c), res1.AffinityKey("tenant"))
+}
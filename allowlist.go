@@ -0,0 +1,287 @@
+package wrmatch
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultPorts of the schemes a URLAllowList knows.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// URLAllowList matches absolute URLs against a list of allowed scheme, host
+// and path patterns. It is meant for SSRF and webhook filtering, e.g.
+//
+//  list := wrmatch.NewURLAllowList().
+//      Add("https://*.example.com/webhooks/*rest").
+//      Add("https://hooks.example.org/notify")
+//  list.Allowed("https://api.example.com/webhooks/a/b") // true
+//
+// Both the entries and the checked URLs are normalized: the scheme and host
+// are lower cased, a missing scheme defaults to https, a missing port to the
+// default port of the scheme, international domain names are converted to
+// punycode and the path is cleaned with CleanPath. A host of the form
+// "*.example.com" matches every subdomain of example.com, but not
+// example.com itself. Paths are matched strictly, without trailing slash or
+// fixed path redirection.
+type URLAllowList struct {
+	// hosts holds the path patterns per "scheme://host:port"
+	hosts map[string]*Pattern
+	// wildcards holds the entries with a "*." host prefix
+	wildcards []allowWildcard
+}
+
+type allowWildcard struct {
+	scheme  string
+	suffix  string // ".example.com"
+	port    string
+	pattern *Pattern
+}
+
+// NewURLAllowList returns a new empty URLAllowList.
+func NewURLAllowList() *URLAllowList {
+	return &URLAllowList{
+		hosts: make(map[string]*Pattern),
+	}
+}
+
+// Add registers an allowed URL pattern of the form
+// "[scheme://]host[:port][/path]", the path uses the pattern syntax of Router.
+// A missing path only allows "/".
+func (l *URLAllowList) Add(rawPattern string) *URLAllowList {
+	scheme, rest := "https", rawPattern
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme, rest = strings.ToLower(rest[:i]), rest[i+3:]
+	}
+	hostport, path := rest, "/"
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		hostport, path = rest[:i], rest[i:]
+	}
+
+	wildcard := strings.HasPrefix(hostport, "*.")
+	if wildcard {
+		hostport = hostport[2:]
+	}
+	host, port, ok := normalizeHostPort(scheme, hostport)
+	if !ok || (wildcard && !strings.Contains(host, ".")) {
+		panic("invalid host '" + hostport + "' in allow list pattern '" + rawPattern + "'")
+	}
+
+	if wildcard {
+		host = "." + host
+		for _, w := range l.wildcards {
+			if w.scheme == scheme && w.suffix == host && w.port == port {
+				addAllowPath(w.pattern, path)
+				return l
+			}
+		}
+		p := newAllowPattern()
+		addAllowPath(p, path)
+		l.wildcards = append(l.wildcards, allowWildcard{scheme, host, port, p})
+		return l
+	}
+
+	key := scheme + "://" + host + ":" + port
+	p := l.hosts[key]
+	if p == nil {
+		p = newAllowPattern()
+		l.hosts[key] = p
+	}
+	addAllowPath(p, path)
+	return l
+}
+
+// Allowed reports whether rawURL matches one of the registered patterns.
+// URLs which are not absolute, contain user information or can not be
+// normalized are never allowed.
+func (l *URLAllowList) Allowed(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Opaque != "" || u.User != nil || u.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host, port, ok := normalizeHostPort(scheme, u.Host)
+	if !ok {
+		return false
+	}
+	path := CleanPath(u.Path)
+
+	if p := l.hosts[scheme+"://"+host+":"+port]; p != nil {
+		if _, _, matched := p.MatchURL(path); matched {
+			return true
+		}
+	}
+	for _, w := range l.wildcards {
+		if w.scheme == scheme && w.port == port &&
+			len(host) > len(w.suffix) && strings.HasSuffix(host, w.suffix) {
+			if _, _, matched := w.pattern.MatchURL(path); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newAllowPattern() *Pattern {
+	return NewPattern(WithDisableRedirectTrailingSlash(), WithDisableRedirectFixedPath())
+}
+
+// addAllowPath adds path to p, unless it is already registered.
+func addAllowPath(p *Pattern, path string) {
+	if p.root.findRoute(path) == nil {
+		p.Add(path, true)
+	}
+}
+
+// normalizeHostPort lower cases the host, converts it to ASCII and adds the
+// default port of the scheme if the port is missing.
+func normalizeHostPort(scheme, hostport string) (host, port string, ok bool) {
+	host = hostport
+	if i := strings.LastIndexByte(hostport, ':'); i >= 0 && !strings.HasSuffix(hostport, "]") {
+		n, err := strconv.Atoi(hostport[i+1:])
+		if err != nil || n <= 0 || n > 65535 {
+			return "", "", false
+		}
+		host, port = hostport[:i], strconv.Itoa(n)
+	}
+	if port == "" {
+		if port, ok = defaultPorts[scheme]; !ok {
+			return "", "", false
+		}
+	}
+
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		ip := net.ParseIP(host[1 : len(host)-1])
+		if ip == nil {
+			return "", "", false
+		}
+		return "[" + ip.String() + "]", port, true
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", "", false
+	}
+	host, ok = toASCIIHost(host)
+	return host, port, ok
+}
+
+// toASCIIHost converts every non ASCII label of host to punycode.
+func toASCIIHost(host string) (string, bool) {
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if label == "" {
+			return "", false
+		}
+		ascii := true
+		for j := 0; j < len(label); j++ {
+			if label[j] >= utf8.RuneSelf {
+				ascii = false
+				break
+			}
+		}
+		if ascii {
+			continue
+		}
+		encoded, ok := punycode(label)
+		if !ok {
+			return "", false
+		}
+		labels[i] = "xn--" + encoded
+	}
+	return strings.Join(labels, "."), true
+}
+
+// punycode encodes s as specified by RFC 3492.
+func punycode(s string) (string, bool) {
+	const (
+		base        = 36
+		tMin        = 1
+		tMax        = 26
+		skew        = 38
+		damp        = 700
+		initialBias = 72
+		initialN    = 128
+	)
+
+	if !utf8.ValidString(s) {
+		return "", false
+	}
+	runes := []rune(s)
+	out := make([]byte, 0, len(s))
+	for _, r := range runes {
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		}
+	}
+	b := len(out)
+	if b > 0 {
+		out = append(out, '-')
+	}
+
+	digit := func(d int) byte {
+		if d < 26 {
+			return byte('a' + d)
+		}
+		return byte('0' + d - 26)
+	}
+	adapt := func(delta, numPoints int, first bool) int {
+		if first {
+			delta /= damp
+		} else {
+			delta /= 2
+		}
+		delta += delta / numPoints
+		k := 0
+		for delta > ((base-tMin)*tMax)/2 {
+			delta /= base - tMin
+			k += base
+		}
+		return k + (base-tMin+1)*delta/(delta+skew)
+	}
+
+	n, delta, bias := initialN, 0, initialBias
+	for h := b; h < len(runes); {
+		m := int(utf8.MaxRune) + 1
+		for _, r := range runes {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+		delta += (m - n) * (h + 1)
+		n = m
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) != n {
+				continue
+			}
+			q := delta
+			for k := base; ; k += base {
+				t := k - bias
+				if t < tMin {
+					t = tMin
+				} else if t > tMax {
+					t = tMax
+				}
+				if q < t {
+					break
+				}
+				out = append(out, digit(t+(q-t)%(base-t)))
+				q = (q - t) / (base - t)
+			}
+			out = append(out, digit(q))
+			bias = adapt(delta, h+1, h == b)
+			delta = 0
+			h++
+		}
+		delta++
+		n++
+	}
+	return string(out), true
+}
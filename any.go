@@ -0,0 +1,61 @@
+package wrmatch
+
+import (
+	"net/http"
+	"sort"
+)
+
+// MethodAny is the method under which the routes added with Router.Any are
+// stored. They are stored once and consulted for every method of the any
+// methods, if the tree of the method itself does not match.
+const MethodAny = "*"
+
+// defaultAnyMethods are the methods matched by Router.Any by default.
+var defaultAnyMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodTrace,
+}
+
+// WithAnyMethods set the methods matched by the routes added with Router.Any.
+// Default: GET, POST, PUT, PATCH, HEAD, OPTIONS, DELETE, CONNECT, TRACE
+func WithAnyMethods(methods ...string) Option {
+	return func(r *Options) {
+		r.anyMethods = methods
+	}
+}
+
+// AnyMethods returns the methods matched by the routes added with Router.Any.
+func (o *Options) AnyMethods() []string {
+	if o.anyMethods == nil {
+		return defaultAnyMethods
+	}
+	return o.anyMethods
+}
+
+// isAnyMethod reports whether method is matched by the routes added with Any.
+func (o *Options) isAnyMethod(method string) bool {
+	for _, m := range o.AnyMethods() {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// insertMethod inserts method into the sorted methods, unless it is contained.
+func insertMethod(methods []string, method string) []string {
+	i := sort.SearchStrings(methods, method)
+	if i == len(methods) || methods[i] != method {
+		methods = append(methods, "")
+		copy(methods[i+1:], methods[i:])
+		methods[i] = method
+	}
+	return methods
+}
@@ -0,0 +1,33 @@
+package wrmatch
+
+import "net/http"
+
+// WithAutoOptions synthesizes the value of OPTIONS requests without an
+// explicit OPTIONS route: if a route matches the path for another method,
+// the match succeeds with the value fn returns for the allowed methods,
+// OPTIONS included, e.g. to answer CORS preflight requests. If fn is nil,
+// the value is the []string of the allowed methods.
+// Default: disable
+func WithAutoOptions(fn func(allowed []string) interface{}) Option {
+	return func(r *Options) {
+		r.autoOptions = true
+		r.optionsValue = fn
+	}
+}
+
+// autoOptionsValue returns the synthesized value of an OPTIONS request for
+// path, if WithAutoOptions is enabled and a route matches the path.
+func (r *Router) autoOptionsValue(method, path string) (interface{}, bool) {
+	if !r.autoOptions || r.normalizeMethod(method) != http.MethodOptions {
+		return nil, false
+	}
+	allowed := r.AllowedMethods(path)
+	if len(allowed) == 0 {
+		return nil, false
+	}
+	allowed = insertMethod(allowed, http.MethodOptions)
+	if r.optionsValue == nil {
+		return allowed, true
+	}
+	return r.optionsValue(allowed), true
+}
@@ -0,0 +1,50 @@
+package wrmatch
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterAutoOptions(t *testing.T) {
+	router := New(WithAutoOptions(nil))
+	router.GET("/user/:name", "get")
+	router.PUT("/user/:name", "put")
+	router.OPTIONS("/explicit", "options")
+	router.GET("/explicit", "get")
+
+	v, ps, matched := router.Match(http.MethodOptions, "/user/gopher")
+	require.True(t, matched)
+	require.Nil(t, ps)
+	require.Equal(t, []string{http.MethodGet, http.MethodOptions, http.MethodPut}, v)
+	res := router.MatchResult(http.MethodOptions, "/user/gopher")
+	require.True(t, res.Matched)
+	require.NoError(t, res.Err())
+	require.True(t, router.MatchRedirect(http.MethodOptions, "/user/gopher").Matched)
+	_, _, err := router.MatchE(http.MethodOptions, "/user/gopher")
+	require.NoError(t, err)
+
+	// explicit routes take precedence
+	v, _, matched = router.Match(http.MethodOptions, "/explicit")
+	require.True(t, matched)
+	require.Equal(t, "options", v)
+
+	_, _, matched = router.Match(http.MethodOptions, "/missing")
+	require.False(t, matched)
+
+	router = New(WithAutoOptions(func(allowed []string) interface{} {
+		return strings.Join(allowed, ", ")
+	}))
+	router.GET("/", "index")
+	v, _, matched = router.Match(http.MethodOptions, "/")
+	require.True(t, matched)
+	require.Equal(t, "GET, OPTIONS", v)
+
+	// disabled by default
+	router = New()
+	router.GET("/", "index")
+	_, _, matched = router.Match(http.MethodOptions, "/")
+	require.False(t, matched)
+}
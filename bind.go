@@ -0,0 +1,129 @@
+package wrmatch
+
+import (
+	"encoding"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParamErrors lists the params Params.Bind failed to bind.
+type ParamErrors []*ParamError
+
+func (e ParamErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strconv.Itoa(len(e)) + " params could not be bound: " + strings.Join(msgs, "; ")
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Bind sets the fields of the struct dst points to, which are tagged with
+// the name of a param, e.g.
+//
+//  type userParams struct {
+//  	ID   int64     `param:"id"`
+//  	Day  time.Time `param:"day" layout:"2006-01-02"`
+//  	Tab  string    `param:"tab,optional"`
+//  }
+//
+// to the converted param values. Fields of string, bool, int, uint and float
+// kinds, time.Time and types implementing encoding.TextUnmarshaler, e.g.
+// UUIDs, are supported. Times are parsed with the layout of the layout tag,
+// time.RFC3339 by default. A missing param is an error, unless the field
+// is tagged optional, its field is left unchanged then.
+// Bind sets all fields it can and returns a ParamErrors listing every
+// missing or invalid param.
+func (ps Params) Bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("bind destination must be a non-nil pointer to a struct")
+	}
+	v = v.Elem()
+	t := v.Type()
+
+	var errs ParamErrors
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("param")
+		if !ok || tag == "-" || field.PkgPath != "" {
+			continue
+		}
+		name, optional := tag, false
+		if j := strings.IndexByte(tag, ','); j >= 0 {
+			name, optional = tag[:j], tag[j+1:] == "optional"
+		}
+
+		value, ok := ps.Get(name)
+		if !ok {
+			if !optional {
+				errs = append(errs, &ParamError{name, "", ErrMissingParam})
+			}
+			continue
+		}
+		if err := setField(v.Field(i), value, field.Tag.Get("layout")); err != nil {
+			errs = append(errs, &ParamError{name, value, err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// setField sets the field f to the converted value.
+func setField(f reflect.Value, value, layout string) error {
+	if f.Type() == timeType {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if reflect.PtrTo(f.Type()).Implements(textUnmarshalerType) {
+		return f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		fl, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(fl)
+	default:
+		return errors.New("unsupported field type " + f.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package wrmatch
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type bindParams struct {
+	Name    string    `param:"name"`
+	ID      int64     `param:"id"`
+	Page    uint8     `param:"page,optional"`
+	Draft   bool      `param:"draft,optional"`
+	Ratio   float32   `param:"ratio,optional"`
+	Day     time.Time `param:"day" layout:"2006-01-02"`
+	IP      net.IP    `param:"ip,optional"`
+	Ignored string
+	Skipped string `param:"-"`
+}
+
+func TestParamsBind(t *testing.T) {
+	ps := Params{
+		{"name", "gopher"}, {"id", "42"}, {"draft", "true"}, {"ratio", "0.5"},
+		{"day", "2021-03-04"}, {"ip", "10.0.0.1"}, {"Ignored", "x"}, {"-", "x"},
+	}
+	dst := bindParams{Page: 1}
+	require.NoError(t, ps.Bind(&dst))
+	require.Equal(t, bindParams{
+		Name:  "gopher",
+		ID:    42,
+		Page:  1,
+		Draft: true,
+		Ratio: 0.5,
+		Day:   time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+		IP:    net.ParseIP("10.0.0.1"),
+	}, dst)
+
+	dst = bindParams{}
+	err := Params{{"id", "x"}, {"page", "256"}, {"day", "2021-03-04"}, {"ip", "nope"}}.Bind(&dst)
+	var errs ParamErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 4)
+	require.Equal(t, "name", errs[0].Key)
+	require.True(t, errors.Is(errs[0], ErrMissingParam))
+	require.Equal(t, "id", errs[1].Key)
+	require.Equal(t, "page", errs[2].Key)
+	require.Equal(t, "ip", errs[3].Key)
+	require.Contains(t, err.Error(), "4 params could not be bound: param 'name': missing param; ")
+	require.Equal(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), dst.Day)
+
+	require.Error(t, ps.Bind(dst))
+	require.Error(t, ps.Bind((*bindParams)(nil)))
+	require.Error(t, Params{{"c", "1"}}.Bind(&struct {
+		C complex64 `param:"c"`
+	}{}))
+}
@@ -0,0 +1,69 @@
+package wrmatch
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// the sizes of the structures of a route, see routeMemSize
+const (
+	nodeSize  = int64(unsafe.Sizeof(node{}))
+	metaSize  = int64(unsafe.Sizeof(routeMeta{}))
+	stateSize = int64(unsafe.Sizeof(RouteState{}))
+	ptrSize   = int64(unsafe.Sizeof(uintptr(0)))
+)
+
+// MemoryBudgetError is the error of a route exceeding the memory budget of
+// a router, see WithMemoryBudget.
+type MemoryBudgetError struct {
+	Method string
+	Path   string
+	// Budget is the memory budget of the router in bytes.
+	Budget int64
+	// Usage is the estimated memory of the routes with the route in bytes.
+	Usage int64
+}
+
+func (e *MemoryBudgetError) Error() string {
+	return "route '" + e.Method + " " + e.Path + "' exceeds the memory budget of " +
+		strconv.FormatInt(e.Budget, 10) + " bytes with " + strconv.FormatInt(e.Usage, 10) + " bytes"
+}
+
+// WithMemoryBudget limits the estimated memory of the routes of a Router to
+// bytes, e.g. to protect shared gateways from unbounded growth of tenant
+// supplied rules. Router.Add panics with a *MemoryBudgetError for a route
+// exceeding the budget, Router.AddE, Router.AddAll and BuildParallel return
+// it. See Router.MemoryUsage for the estimate.
+// Default: unlimited
+func WithMemoryBudget(bytes int64) Option {
+	return func(r *Options) {
+		r.memoryBudget = bytes
+	}
+}
+
+// MemoryUsage returns the estimated memory of the routes in bytes. It is the
+// sum of the estimates of the single routes, which count the nodes, the
+// metadata and the path of a route. Values and overlays are not included.
+func (r *Router) MemoryUsage() int64 {
+	return r.memoryUsage
+}
+
+// routeMemSize estimates the memory of the route with path: its leaf, the
+// nodes of its wildcards and the nodes split by them, each referenced by
+// its parent, the metadata and the path.
+func routeMemSize(path string) int64 {
+	nodes := 1 + 2*int64(countParams(path))
+	return nodes*(nodeSize+ptrSize+1) + metaSize + stateSize + int64(len(path))
+}
+
+// checkBudget returns the error of adding the route with method and path to
+// routes with the estimated memory usage.
+func (o *Options) checkBudget(method, path string, usage int64) error {
+	if o.memoryBudget <= 0 {
+		return nil
+	}
+	if usage += routeMemSize(path); usage > o.memoryBudget {
+		return &MemoryBudgetError{method, path, o.memoryBudget, usage}
+	}
+	return nil
+}
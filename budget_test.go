@@ -0,0 +1,37 @@
+package wrmatch
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterMemoryBudget(t *testing.T) {
+	budget := routeMemSize("/a") + routeMemSize("/user/:name")
+	router := New(WithMemoryBudget(budget))
+	router.GET("/a", "a")
+	router.GET("/user/:name", "user")
+	require.Equal(t, budget, router.MemoryUsage())
+
+	err := router.AddE(http.MethodGet, "/b", "b")
+	require.IsType(t, &MemoryBudgetError{}, err)
+	require.EqualError(t, err, "route 'GET /b' exceeds the memory budget of "+
+		strconv.FormatInt(budget, 10)+" bytes with "+strconv.FormatInt(budget+routeMemSize("/b"), 10)+" bytes")
+	require.Panics(t, func() { router.GET("/b", "b") })
+
+	// removed routes free the budget
+	require.True(t, router.Remove(http.MethodGet, "/user/:name"))
+	require.Equal(t, routeMemSize("/a"), router.MemoryUsage())
+	require.NoError(t, router.AddE(http.MethodGet, "/b", "b"))
+
+	// wildcards are more expensive
+	require.Greater(t, routeMemSize("/user/:name"), routeMemSize("/user/gopher"))
+
+	_, err = BuildParallel([]Route{
+		{Method: http.MethodGet, Path: "/a", Value: "a"},
+		{Method: http.MethodGet, Path: "/b", Value: "b"},
+	}, 2, WithMemoryBudget(routeMemSize("/a")))
+	require.IsType(t, &MemoryBudgetError{}, err)
+}
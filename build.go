@@ -0,0 +1,280 @@
+package wrmatch
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// buildGroup holds the routes of a method whose paths start with the same
+// byte after the leading '/', they are built into a separate tree.
+type buildGroup struct {
+	method string
+	first  byte
+	routes []Route
+	root   *node
+	err    error
+}
+
+// BuildParallel returns a new router with the given options holding the
+// routes, the trees are built concurrently by up to workers goroutines.
+// The routes are partitioned by method and by the first byte of their path
+// after the leading '/', the escaped one for an escaped ':' or '*', the
+// trees of the partitions are stitched afterwards.
+// The paths are normalized like by Add, a route with optional segments or
+// parameters is registered for each of its variants.
+// The disabled state and the name of the routes are kept.
+// Like AddE, it returns an error instead of panicking, if a route can not be
+// registered.
+func BuildParallel(routes []Route, workers int, opts ...Option) (*Router, error) {
+	r := New(opts...)
+	if workers < 1 {
+		workers = 1
+	}
+
+	groups := make(map[string]*buildGroup)
+	roots := make(map[string]*Route)
+	sequential := make(map[string]bool)
+	catchAlls := 0
+	var usage int64
+	normalized := make([]Route, 0, len(routes))
+	for i := range routes {
+		route := routes[i]
+		route.Method = r.normalizeMethod(route.Method)
+		if route.Method == "" {
+			return nil, errors.New("method must not be empty")
+		}
+		if len(route.Path) < 1 || route.Path[0] != '/' {
+			return nil, errors.New("path must begin with '/' in path '" + route.Path + "'")
+		}
+		if route.Value == nil {
+			return nil, errors.New("value must not be nil")
+		}
+		var expanded []defaultRoute
+		if err := registrationError(func() {
+			route.Path = nameWildcards(r.convertPath(route.Path))
+			expanded = expandRoutes(route.Path)
+		}); err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, route)
+		if r.policy != nil {
+			if err := r.policy.check(route.Method, route.Path, i, catchAlls); err != nil {
+				return nil, err
+			}
+		}
+		if isCatchAll(route.Path) {
+			catchAlls++
+		}
+		if err := r.checkBudget(route.Method, route.Path, usage); err != nil {
+			return nil, err
+		}
+		usage += routeMemSize(route.Path)
+		// the variants of optional parts are added by add
+		if len(expanded) > 1 || expanded[0].path != route.Path {
+			sequential[route.Method] = true
+			continue
+		}
+
+		if route.Path == "/" {
+			if roots[route.Method] != nil {
+				return nil, errors.New("a value is already registered for path '/'")
+			}
+			roots[route.Method] = &route
+			continue
+		}
+		// a wildcard below the root can not be stitched
+		if c := route.Path[1]; c == ':' || c == '*' {
+			sequential[route.Method] = true
+		}
+		first := firstByte(route.Path[1:])
+		key := route.Method + string(first)
+		g := groups[key]
+		if g == nil {
+			g = &buildGroup{method: route.Method, first: first}
+			groups[key] = g
+		}
+		g.routes = append(g.routes, route)
+	}
+
+	// build the trees of the groups
+	var sorted []*buildGroup
+	for _, g := range groups {
+		if !sequential[g.method] {
+			sorted = append(sorted, g)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].method < sorted[j].method ||
+			(sorted[i].method == sorted[j].method && sorted[i].first < sorted[j].first)
+	})
+	jobs := make(chan *buildGroup)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for g := range jobs {
+				g.root, g.err = r.buildTree(g.routes)
+			}
+		}()
+	}
+	for _, g := range sorted {
+		jobs <- g
+	}
+	close(jobs)
+	wg.Wait()
+
+	// stitch the trees
+	for _, g := range sorted {
+		if g.err != nil {
+			return nil, g.err
+		}
+	}
+	r.trees = make(map[string]*node)
+	for _, g := range sorted {
+		r.trees[g.method] = stitchTree(r.trees[g.method], g.root)
+	}
+	for method, route := range roots {
+		if sequential[method] {
+			continue
+		}
+		root, err := r.buildTree([]Route{*route})
+		if err != nil {
+			return nil, err
+		}
+		r.trees[method] = stitchTree(r.trees[method], root)
+	}
+
+	// methods with a wildcard below the root or optional parts are built one
+	// by one
+	for _, route := range normalized {
+		if !sequential[route.Method] {
+			continue
+		}
+		if err := registrationError(func() { r.add(route.Method, route.Path, route.Value) }); err != nil {
+			return nil, err
+		}
+		for _, expanded := range expandRoutes(route.Path) {
+			r.findRoute(route.Method, expanded.path).routeMeta().disabled = route.Disabled
+		}
+		r.findRoute(r.last.method, r.last.path).routeMeta().name = route.Name
+	}
+
+	for method, root := range r.trees {
+		var err error
+		root.walk("", func(path string, n *node) bool {
+			if name := n.routeName(); name != "" {
+				n.meta.name = ""
+				err = registrationError(func() { r.setName(method, path, name) })
+			}
+			return err == nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		r.reindex(method)
+	}
+	r.updateMaxParams()
+	if r.maxParams > 0 {
+		r.paramsNew = func() *Params {
+			ps := make(Params, 0, r.maxParams)
+			return &ps
+		}
+	}
+	return r, nil
+}
+
+// buildTree builds a separate tree holding the routes.
+func (r *Router) buildTree(routes []Route) (root *node, err error) {
+	root = new(node)
+	err = registrationError(func() {
+		for _, route := range routes {
+			value := route.Value
+			if r.saveMatchedRoutePath {
+				value = matchValue{route.Path, value}
+			}
+			r.checkConstraints(route.Path)
+			root.addRoute(route.Path, value)
+			meta := root.findRoute(route.Path).routeMeta()
+			meta.route = route.Path
+			meta.stats = r.newWildcardStats(route.Path)
+			meta.disabled = route.Disabled
+			meta.name = route.Name
+		}
+	})
+	return root, err
+}
+
+// registrationError calls fn and returns the registration error it panics
+// with, if any.
+func registrationError(fn func()) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			switch e := rec.(type) {
+			case error:
+				err = e
+			case string:
+				err = errors.New(e)
+			default:
+				panic(rec)
+			}
+		}
+	}()
+	fn()
+	return nil
+}
+
+// stitchTree adds the tree sub, whose paths start with "/" and either all
+// continue with the same byte or are "/" only, to the tree t, which does not
+// hold any path of sub. A sub tree "/" is merged into t with its children.
+func stitchTree(t, sub *node) *node {
+	if t == nil {
+		return sub
+	}
+	if t.path != "/" {
+		// the common prefix of the trees is "/"
+		child := t
+		child.path = child.path[1:]
+		child.nType = static
+		t = &node{
+			path:     "/",
+			indices:  child.path[:1],
+			nType:    root,
+			priority: child.priority,
+			children: []*node{child},
+		}
+	}
+	t.priority += sub.priority
+	if sub.path == "/" {
+		if sub.value != nil {
+			t.value = sub.value
+			t.meta = sub.meta
+		}
+		for _, child := range sub.children {
+			t = stitchChild(t, child)
+		}
+		return t
+	}
+
+	child := sub
+	child.path = child.path[1:]
+	child.nType = static
+	return stitchChild(t, child)
+}
+
+// stitchChild adds child, whose path continues the path "/" of t with a byte
+// none of the children of t starts with, to the children of t.
+func stitchChild(t, child *node) *node {
+	t.indices += child.path[:1]
+	t.children = append(t.children, child)
+
+	// keep the children ordered by priority
+	for i := len(t.children) - 1; i > 0 && t.children[i-1].priority < t.children[i].priority; i-- {
+		t.children[i-1], t.children[i] = t.children[i], t.children[i-1]
+		b := []byte(t.indices)
+		b[i-1], b[i] = b[i], b[i-1]
+		t.indices = string(b)
+	}
+	return t
+}
@@ -0,0 +1,179 @@
+package wrmatch
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildParallel(t *testing.T) {
+	paths := []string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/cmd/:tool/",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/doc/",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/info/:user/public",
+		"/info/:user/project/:project",
+		"/uploads",
+		"/users/:id",
+	}
+	var routes []Route
+	for _, path := range paths {
+		routes = append(routes, Route{Method: http.MethodGet, Path: path, Value: path})
+	}
+	for i := 0; i < 100; i++ {
+		path := fmt.Sprintf("/gen%d/:id", i)
+		routes = append(routes, Route{Method: http.MethodPost, Path: path, Value: path})
+	}
+	routes = append(routes,
+		Route{Method: http.MethodPut, Path: "/:id", Value: "put", Name: "put"},
+		Route{Method: http.MethodPut, Path: "/", Value: "put-root"},
+		Route{Method: http.MethodDelete, Path: "/", Value: "delete-root", Disabled: true},
+		Route{Method: http.MethodPatch, Path: "/patch", Value: "patch", Name: "patch"},
+	)
+
+	want := New(WithSaveMatchedRoutePath(), WithFirstSegmentIndex())
+	for _, route := range routes {
+		want.Add(route.Method, route.Path, route.Value)
+		if route.Disabled {
+			want.Disable(route.Method, route.Path)
+		}
+		if route.Name != "" {
+			want.Name(route.Name)
+		}
+	}
+
+	router, err := BuildParallel(routes, 4, WithSaveMatchedRoutePath(), WithFirstSegmentIndex())
+	require.NoError(t, err)
+	require.Equal(t, want.Routes(), router.Routes())
+	require.Equal(t, want.maxParams, router.maxParams)
+	for _, root := range router.trees {
+		checkPriorities(t, root)
+	}
+
+	for _, route := range routes {
+		for _, path := range []string{route.Path, route.Path + "x", route.Path + "/"} {
+			v1, ps1, matched1 := want.Match(route.Method, path)
+			v2, ps2, matched2 := router.Match(route.Method, path)
+			require.Equal(t, matched1, matched2, path)
+			require.Equal(t, v1, v2, path)
+			require.Equal(t, ps1, ps2, path)
+		}
+	}
+	url, err := router.URLFor("patch", nil)
+	require.NoError(t, err)
+	require.Equal(t, "/patch", url)
+
+	_, err = BuildParallel([]Route{
+		{Method: http.MethodGet, Path: "/user/:name", Value: "a"},
+		{Method: http.MethodGet, Path: "/user/:id", Value: "b"},
+	}, 2)
+	_, ok := err.(*ConflictError)
+	require.True(t, ok)
+
+	_, err = BuildParallel([]Route{
+		{Method: http.MethodGet, Path: "/", Value: "a"},
+		{Method: http.MethodGet, Path: "/", Value: "b"},
+	}, 2)
+	require.EqualError(t, err, "a value is already registered for path '/'")
+
+	_, err = BuildParallel([]Route{{Method: http.MethodGet, Path: "/a", Value: "a", Name: "x"}, {Method: http.MethodGet, Path: "/b", Value: "b", Name: "x"}}, 2)
+	require.Error(t, err)
+}
+
+func TestBuildParallelSyntax(t *testing.T) {
+	tests := []struct {
+		opts   []Option
+		routes []Route
+		paths  []string
+	}{
+		{
+			routes: []Route{
+				{Method: http.MethodGet, Path: "/posts/:page=1", Value: "posts", Name: "posts"},
+				{Method: http.MethodGet, Path: "/users/:id?", Value: "users", Disabled: true},
+				{Method: http.MethodGet, Path: "/api(/v1)/items", Value: "items"},
+				{Method: http.MethodGet, Path: "/orgs/:org/*/settings", Value: "settings"},
+				{Method: http.MethodGet, Path: "/files/**/meta.json", Value: "meta"},
+				{Method: http.MethodGet, Path: "/blobs/*path/raw", Value: "raw"},
+				{Method: http.MethodGet, Path: "/time\\:now", Value: "now"},
+				{Method: http.MethodPost, Path: "/static", Value: "static"},
+			},
+			paths: []string{
+				"/posts", "/posts/2", "/users", "/users/1", "/api/items", "/api/v1/items",
+				"/orgs/go/x/settings", "/files/a/b/meta.json", "/blobs/a/b/raw",
+				"/time:now", "/static",
+			},
+		},
+		{
+			opts: []Option{WithServeMuxPatterns()},
+			routes: []Route{
+				{Method: http.MethodGet, Path: "/users/{id}/files/{path...}", Value: "files"},
+				{Method: http.MethodGet, Path: "/docs/{$}", Value: "docs"},
+				{Method: http.MethodGet, Path: "/a:b", Value: "ab"},
+			},
+			paths: []string{"/users/1/files/a/b", "/docs/", "/a:b"},
+		},
+		{
+			routes: []Route{
+				{Method: http.MethodGet, Path: "/\\:a", Value: "a"},
+				{Method: http.MethodGet, Path: "/\\*b", Value: "b"},
+				{Method: http.MethodGet, Path: "/A", Value: "A"},
+			},
+			paths: []string{"/:a", "/*b", "/A"},
+		},
+	}
+	for _, tt := range tests {
+		want := New(tt.opts...)
+		for _, route := range tt.routes {
+			want.Add(route.Method, route.Path, route.Value)
+			if route.Name != "" {
+				want.Name(route.Name)
+			}
+		}
+		for _, route := range want.Routes() {
+			for _, r := range tt.routes {
+				if r.Disabled && r.Value == route.Value {
+					want.Disable(route.Method, route.Path)
+				}
+			}
+		}
+
+		router, err := BuildParallel(tt.routes, 4, tt.opts...)
+		require.NoError(t, err)
+		require.Equal(t, want.Routes(), router.Routes())
+		require.Equal(t, want.maxParams, router.maxParams)
+		for _, path := range tt.paths {
+			for _, method := range []string{http.MethodGet, http.MethodPost} {
+				v1, ps1, matched1 := want.Match(method, path)
+				v2, ps2, matched2 := router.Match(method, path)
+				require.Equal(t, matched1, matched2, path)
+				require.Equal(t, v1, v2, path)
+				require.Equal(t, ps1, ps2, path)
+			}
+		}
+	}
+
+	router, err := BuildParallel([]Route{{Method: http.MethodGet, Path: "/posts/:page=1", Value: "posts", Name: "posts"}}, 2)
+	require.NoError(t, err)
+	_, ps, matched := router.Match(http.MethodGet, "/posts")
+	require.True(t, matched)
+	require.Equal(t, Params{{"page", "1"}}, ps)
+	url, err := router.URLFor("posts", map[string]string{"page": "2"})
+	require.NoError(t, err)
+	require.Equal(t, "/posts/2", url)
+
+	_, err = BuildParallel([]Route{{Method: http.MethodGet, Path: "/api(/v1/items", Value: "items"}}, 2)
+	require.EqualError(t, err, "optional segment is not closed in path '/api(/v1/items'")
+	_, err = BuildParallel([]Route{{Method: http.MethodGet, Path: "/users/{id", Value: "users"}}, 2, WithServeMuxPatterns())
+	require.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package wrmatch
+
+import (
+	"time"
+)
+
+// CacheHint is caching metadata attached to a route, e.g. for CDN like
+// middlewares which key their caching decisions by the matched route.
+type CacheHint struct {
+	// Cacheable reports whether responses of the route may be cached.
+	Cacheable bool
+	// TTL is the time responses of the route may be cached.
+	TTL time.Duration
+	// Vary lists the names of the url params the cached responses vary by.
+	Vary []string
+}
+
+// SetCacheHint attaches hint to the route registered with the given method
+// and path. The path must be the registered pattern, not a request path.
+// It reports whether a value was registered for the method and path.
+func (r *Router) SetCacheHint(method, path string, hint CacheHint) bool {
+	r.checkSealed()
+	n := r.findRoute(method, path)
+	if n == nil {
+		return false
+	}
+	n.routeMeta().cacheHint = &hint
+	return true
+}
+
+// CacheHint returns the cache hint attached to the route registered with
+// the given method and path, and whether there is one.
+func (r *Router) CacheHint(method, path string) (CacheHint, bool) {
+	if n := r.findRoute(method, path); n != nil && n.meta != nil && n.meta.cacheHint != nil {
+		return *n.meta.cacheHint, true
+	}
+	return CacheHint{}, false
+}
+
+// CacheHint returns the cache hint attached to the matched route,
+// and whether there is one.
+func (m *MatchResult) CacheHint() (CacheHint, bool) {
+	if m.meta == nil || m.meta.cacheHint == nil {
+		return CacheHint{}, false
+	}
+	return *m.meta.cacheHint, true
+}
+
+// CacheVary returns the values of the url params listed in the Vary of
+// the cache hint of the matched route, in the order of Vary.
+func (m *MatchResult) CacheVary() []string {
+	hint, ok := m.CacheHint()
+	if !ok || len(hint.Vary) == 0 {
+		return nil
+	}
+	values := make([]string, len(hint.Vary))
+	for i, name := range hint.Vary {
+		values[i] = m.Params.Param(name)
+	}
+	return values
+}
@@ -0,0 +1,48 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterCacheHint(t *testing.T) {
+	router := New()
+	router.GET("/user/:name/posts/:post", "post")
+	router.GET("/user/:name", "user")
+
+	hint := CacheHint{Cacheable: true, TTL: time.Minute, Vary: []string{"post", "name"}}
+	require.False(t, router.SetCacheHint(http.MethodGet, "/user/gopher", hint))
+	require.False(t, router.SetCacheHint(http.MethodPost, "/user/:name", hint))
+	require.True(t, router.SetCacheHint(http.MethodGet, "/user/:name/posts/:post", hint))
+
+	got, ok := router.CacheHint(http.MethodGet, "/user/:name/posts/:post")
+	require.True(t, ok)
+	require.Equal(t, hint, got)
+	_, ok = router.CacheHint(http.MethodGet, "/user/:name")
+	require.False(t, ok)
+
+	// the hint is kept when the tree is split by later registrations
+	router.GET("/user/:name/posts/:post/comments", "comments")
+
+	res := router.MatchResult(http.MethodGet, "/user/gopher/posts/42")
+	require.True(t, res.Matched)
+	require.Equal(t, "post", res.Value)
+	got, ok = res.CacheHint()
+	require.True(t, ok)
+	require.Equal(t, hint, got)
+	require.Equal(t, []string{"42", "gopher"}, res.CacheVary())
+
+	res = router.MatchResult(http.MethodGet, "/user/gopher")
+	require.True(t, res.Matched)
+	_, ok = res.CacheHint()
+	require.False(t, ok)
+	require.Nil(t, res.CacheVary())
+
+	res = router.MatchResult(http.MethodGet, "/notfound")
+	require.False(t, res.Matched)
+	_, ok = res.CacheHint()
+	require.False(t, ok)
+}
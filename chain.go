@@ -0,0 +1,37 @@
+package wrmatch
+
+// Matcher matches a method and a path to a value.
+type Matcher interface {
+	Match(method, path string) (interface{}, Params, bool)
+}
+
+// RouterChain is a Matcher which queries several routers in order,
+// e.g. layered rule sources like user rules, team rules and global defaults.
+type RouterChain struct {
+	routers []*Router
+}
+
+var _ Matcher = (*Router)(nil)
+var _ Matcher = (*RouterChain)(nil)
+
+// Chain returns a RouterChain which tries each router in the given order.
+func Chain(routers ...*Router) *RouterChain {
+	return &RouterChain{routers}
+}
+
+// Match returns the match of the first router which matches method and path.
+func (c *RouterChain) Match(method, path string) (interface{}, Params, bool) {
+	value, ps, _, matched := c.MatchIndex(method, path)
+	return value, ps, matched
+}
+
+// MatchIndex is like Match, but additionally returns the index of the router
+// which matched, -1 if no router matched.
+func (c *RouterChain) MatchIndex(method, path string) (interface{}, Params, int, bool) {
+	for i, r := range c.routers {
+		if value, ps, matched := r.Match(method, path); matched {
+			return value, ps, i, true
+		}
+	}
+	return nil, nil, -1, false
+}
@@ -0,0 +1,44 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChain(t *testing.T) {
+	user := New()
+	user.GET("/user/:name", "user-rule")
+
+	team := New()
+	team.GET("/user/:name", "team-rule")
+	team.GET("/team", "team")
+
+	global := New()
+	global.GET("/*all", "default")
+
+	chain := Chain(user, team, global)
+
+	v, ps, i, matched := chain.MatchIndex(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, 0, i)
+	require.Equal(t, "user-rule", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+
+	v, _, i, matched = chain.MatchIndex(http.MethodGet, "/team")
+	require.True(t, matched)
+	require.Equal(t, 1, i)
+	require.Equal(t, "team", v)
+
+	v, ps, matched = chain.Match(http.MethodGet, "/other/path")
+	require.True(t, matched)
+	require.Equal(t, "default", v)
+	require.Equal(t, Params{Param{"all", "/other/path"}}, ps)
+
+	v, ps, i, matched = chain.MatchIndex(http.MethodPost, "/team")
+	require.False(t, matched)
+	require.Equal(t, -1, i)
+	require.Nil(t, v)
+	require.Nil(t, ps)
+}
@@ -0,0 +1,57 @@
+package wrmatch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ValueCodec encodes and decodes route values, so that features which
+// (de)serialize the route table can handle arbitrary value types.
+type ValueCodec interface {
+	Encode(value interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+// StringCodec is a ValueCodec for string values. It is the default codec.
+type StringCodec struct{}
+
+// Encode implements ValueCodec, value must be a string.
+func (StringCodec) Encode(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("string codec: unsupported value type %T", value)
+	}
+	return []byte(s), nil
+}
+
+// Decode implements ValueCodec, the value is always a string.
+func (StringCodec) Decode(data []byte) (interface{}, error) {
+	return string(data), nil
+}
+
+// JSONCodec is a ValueCodec which encodes values as JSON.
+type JSONCodec struct {
+	// New returns a pointer to the value to decode into.
+	// If nil, values are decoded into the generic JSON types
+	// (map[string]interface{}, []interface{}, float64, string, bool).
+	New func() interface{}
+}
+
+// Encode implements ValueCodec.
+func (c JSONCodec) Encode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode implements ValueCodec.
+func (c JSONCodec) Decode(data []byte) (interface{}, error) {
+	if c.New == nil {
+		var value interface{}
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+	value := c.New()
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
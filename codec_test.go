@@ -0,0 +1,50 @@
+package wrmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringCodec(t *testing.T) {
+	var c StringCodec
+
+	b, err := c.Encode("value")
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), b)
+
+	v, err := c.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, "value", v)
+
+	_, err = c.Encode(1)
+	require.Error(t, err)
+}
+
+func TestJSONCodec(t *testing.T) {
+	type target struct {
+		Backend string `json:"backend"`
+	}
+
+	c := JSONCodec{}
+	b, err := c.Encode(target{"a"})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"backend":"a"}`, string(b))
+
+	v, err := c.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"backend": "a"}, v)
+
+	c = JSONCodec{New: func() interface{} { return &target{} }}
+	v, err = c.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, &target{"a"}, v)
+
+	_, err = c.Decode([]byte("{"))
+	require.Error(t, err)
+}
+
+func TestWithValueCodec(t *testing.T) {
+	require.Equal(t, StringCodec{}, New().ValueCodec())
+	require.Equal(t, JSONCodec{}, NewPattern(WithValueCodec(JSONCodec{})).ValueCodec())
+}
@@ -0,0 +1,213 @@
+package wrmatch
+
+import (
+	"strings"
+	"time"
+)
+
+// WithConstraint registers a custom constraint, which named parameters can
+// reference like the built-in ones, e.g. "/posts/:name<slug>" or
+// "/posts/:name@slug" for
+//
+//  wrmatch.WithConstraint("slug", isSlug)
+//
+// so domain-specific validation stays in the matcher. Like for the built-in
+// constraints, a value not satisfying the constraint does not match the route.
+// The name consists of ASCII letters, digits, '_' and '-', it must not be the
+// one of a built-in constraint.
+func WithConstraint(name string, fn func(value string) bool) Option {
+	checkCustomConstraint(name, fn)
+	return func(r *Options) {
+		r.addConstraint(name, fn)
+	}
+}
+
+// Constraint registers a custom constraint like WithConstraint, the routes
+// added afterwards can reference it.
+func (r *Router) Constraint(name string, fn func(value string) bool) *Router {
+	r.checkSealed()
+	checkCustomConstraint(name, fn)
+	r.addConstraint(name, fn)
+	return r
+}
+
+// checkCustomConstraint panics if a custom constraint can not be registered
+// with name and fn. The name must not contain the bytes of the path syntax,
+// e.g. '.' starting the literal suffix of a param.
+func checkCustomConstraint(name string, fn func(value string) bool) {
+	if name == "" {
+		panic("invalid constraint name '" + name + "'")
+	}
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; !isAlnum(c) && c != '_' && c != '-' {
+			panic("invalid constraint name '" + name + "'")
+		}
+	}
+	if validConstraint(name) {
+		panic("constraint '" + name + "' is built-in")
+	}
+	if fn == nil {
+		panic("constraint '" + name + "' must not be nil")
+	}
+}
+
+// addConstraint registers the custom constraint, the constraints may be
+// shared with clones, so they are copied.
+func (o *Options) addConstraint(name string, fn func(value string) bool) {
+	constraints := make(map[string]func(string) bool, len(o.constraints)+1)
+	for k, v := range o.constraints {
+		constraints[k] = v
+	}
+	constraints[name] = fn
+	o.constraints = constraints
+}
+
+// hasConstraint reports whether constraint is a built-in or a registered
+// constraint, o may be nil.
+func (o *Options) hasConstraint(constraint string) bool {
+	if o != nil && o.constraints[constraint] != nil {
+		return true
+	}
+	return validConstraint(constraint)
+}
+
+// satisfies reports whether the param value satisfies the built-in or
+// registered constraint, o may be nil.
+func (o *Options) satisfies(constraint, value string) bool {
+	if o != nil {
+		if fn := o.constraints[constraint]; fn != nil {
+			return fn(value)
+		}
+	}
+	return checkConstraint(constraint, value)
+}
+
+// checkConstraints panics if a named parameter of path references a
+// constraint which is neither built-in nor registered.
+func (o *Options) checkConstraints(path string) {
+	for rest := path; ; {
+		wildcard, i, _ := findWildcard(rest)
+		if i < 0 {
+			return
+		}
+		name, _ := splitSuffix(wildcard[1:])
+		if name, constraint := splitConstraint(name); constraint != "" && !o.hasConstraint(constraint) {
+			panic("unknown constraint '" + constraint + "' of wildcard '" + name + "' in path '" + path + "'")
+		}
+		rest = rest[i+len(wildcard):]
+	}
+}
+
+// unconstrainedParam returns the name of the first named parameter of path
+// without a constraint, empty if there is none.
+func unconstrainedParam(path string) string {
+	for rest := path; ; {
+		wildcard, i, _ := findWildcard(rest)
+		if i < 0 {
+			return ""
+		}
+		name, _ := splitSuffix(wildcard[1:])
+		if name, constraint := splitConstraint(name); wildcard[0] == ':' && constraint == "" {
+			return name
+		}
+		rest = rest[i+len(wildcard):]
+	}
+}
+
+// splitConstraint splits the name of a named parameter with a constraint,
+// e.g. "id<int>" or "id@int", into the name and the constraint. The
+// constraint is empty, if there is none.
+func splitConstraint(name string) (string, string) {
+	if len(name) > 0 && name[len(name)-1] == '>' {
+		if i := strings.IndexByte(name, '<'); i >= 0 {
+			return name[:i], name[i+1 : len(name)-1]
+		}
+	}
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// validConstraint reports whether constraint is a built-in constraint.
+func validConstraint(constraint string) bool {
+	switch constraint {
+	case "int", "uuid", "alpha", "hex", "date":
+		return true
+	}
+	return false
+}
+
+// checkConstraint reports whether the param value satisfies the built-in
+// constraint:
+//
+//  int    optionally signed decimal digits, e.g. -42
+//  uuid   a hyphenated UUID, e.g. 123e4567-e89b-12d3-a456-426614174000
+//  alpha  ASCII letters
+//  hex    hexadecimal digits
+//  date   a date of the form 2006-01-02
+func checkConstraint(constraint, value string) bool {
+	switch constraint {
+	case "int":
+		if len(value) > 1 && value[0] == '-' {
+			value = value[1:]
+		}
+		return isDigits(value)
+	case "uuid":
+		if len(value) != 36 {
+			return false
+		}
+		for i := 0; i < len(value); i++ {
+			if i == 8 || i == 13 || i == 18 || i == 23 {
+				if value[i] != '-' {
+					return false
+				}
+			} else if !isHex(value[i]) {
+				return false
+			}
+		}
+		return true
+	case "alpha":
+		for i := 0; i < len(value); i++ {
+			if c := value[i] | 0x20; c < 'a' || c > 'z' {
+				return false
+			}
+		}
+		return value != ""
+	case "hex":
+		for i := 0; i < len(value); i++ {
+			if !isHex(value[i]) {
+				return false
+			}
+		}
+		return value != ""
+	case "date":
+		if len(value) != 10 || !isDigits(value[:4]) || value[4] != '-' || !isDigits(value[5:7]) ||
+			value[7] != '-' || !isDigits(value[8:]) {
+			return false
+		}
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	}
+	return false
+}
+
+// isDigits reports whether s is a non-empty string of decimal digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isAlnum reports whether c is an ASCII letter or a decimal digit.
+func isAlnum(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isHex reports whether c is a hexadecimal digit.
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
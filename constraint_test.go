@@ -0,0 +1,211 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		valid      []string
+		invalid    []string
+	}{
+		{"int", []string{"0", "42", "-1"}, []string{"", "-", "1.5", "a1", "+1"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"},
+			[]string{"", "123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g"}},
+		{"alpha", []string{"a", "Gopher"}, []string{"", "a1", "a-b", "@"}},
+		{"hex", []string{"0", "1f", "DEADbeef"}, []string{"", "0x1f", "g"}},
+		{"date", []string{"2021-03-04", "2020-02-29"}, []string{"", "2021-3-4", "2021-02-30", "2021-13-01", "20210304xx"}},
+		{"unknown", nil, []string{"1", "a"}},
+	}
+	for _, tt := range tests {
+		for _, value := range tt.valid {
+			require.True(t, checkConstraint(tt.constraint, value), tt.constraint+" "+value)
+		}
+		for _, value := range tt.invalid {
+			require.False(t, checkConstraint(tt.constraint, value), tt.constraint+" "+value)
+		}
+	}
+}
+
+func TestRouterConstraints(t *testing.T) {
+	router := New()
+	router.GET("/user/:id<int>", "user")
+	router.GET("/user/:id<int>/posts/:day<date>", "posts").Name("posts")
+	router.GET("/files/:sum<hex>/*filepath", "files")
+
+	tests := []struct {
+		path    string
+		value   string
+		params  Params
+		matched bool
+	}{
+		{"/user/42", "user", Params{{"id", "42"}}, true},
+		{"/user/gopher", "", nil, false},
+		{"/user/42/posts/2021-03-04", "posts", Params{{"id", "42"}, {"day", "2021-03-04"}}, true},
+		{"/user/42/posts/yesterday", "", nil, false},
+		{"/files/1f/a/b", "files", Params{{"sum", "1f"}, {"filepath", "/a/b"}}, true},
+		{"/files/xyz/a/b", "", nil, false},
+	}
+	for _, tt := range tests {
+		v, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.Equal(t, tt.matched, matched, tt.path)
+		if tt.matched {
+			require.Equal(t, tt.value, v, tt.path)
+			require.Equal(t, tt.params, ps, tt.path)
+		}
+	}
+
+	_, view, matched := router.MatchView(http.MethodGet, "/user/42")
+	require.True(t, matched)
+	require.Equal(t, "42", view.Param("id"))
+	require.Equal(t, "/user/:id<int>", router.Routes()[1].Path)
+	require.Equal(t, []string{"id", "day"}, router.WildcardReport()[2].Params)
+
+	url, err := router.URLFor("posts", map[string]string{"id": "1", "day": "2021-03-04"})
+	require.NoError(t, err)
+	require.Equal(t, "/user/1/posts/2021-03-04", url)
+	_, err = router.URLFor("posts", map[string]string{"id": "one", "day": "2021-03-04"})
+	require.EqualError(t, err, "param 'id' is not a valid int")
+
+	require.Panics(t, func() { New().GET("/user/:id<number>", "user") })
+	require.Panics(t, func() { New().GET("/user/:id<int", "user") })
+	require.Panics(t, func() { New().GET("/files/*filepath<hex>", "files") })
+	require.Panics(t, func() { New().GET("/user/:id<int>", "a").GET("/user/:id<uuid>/x", "b") })
+	require.Panics(t, func() { New().GET("/user/:id<int>/:id<hex>", "user") })
+}
+
+func TestRouterConstraintsFixedPath(t *testing.T) {
+	router := New()
+	router.GET("/Users/:id<int>/", "user")
+	router.GET("/users/:id<int>/posts", "posts")
+
+	// the case-insensitive lookup checks the constraints
+	_, found := router.trees[http.MethodGet].findCaseInsensitivePathFor("/users/abc", true, &router.Options)
+	require.False(t, found)
+	_, _, matched := router.Match(http.MethodGet, "/users/abc")
+	require.False(t, matched)
+	require.Equal(t, "", router.MatchRedirect(http.MethodGet, "/users/abc").Redirect)
+
+	v, ps, matched := router.Match(http.MethodGet, "/USERS/7")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{{"id", "7"}}, ps)
+
+	pattern := NewPattern()
+	pattern.Add("/Users/:id<int>/", "user")
+	pattern.Add("/users/:id<int>/posts", "posts")
+	_, _, matched = pattern.Match("/users/abc")
+	require.False(t, matched)
+	v, _, matched = pattern.Match("/USERS/7")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+}
+
+func isSlug(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; (c < 'a' || c > 'z') && c != '-' {
+			return false
+		}
+	}
+	return value != ""
+}
+
+func TestRouterCustomConstraint(t *testing.T) {
+	router := New(WithConstraint("slug", isSlug))
+	router.GET("/posts/:name<slug>", "post").Name("post")
+	router.Any("/posts/:id", "any")
+
+	v, ps, matched := router.Match(http.MethodGet, "/posts/hello-world")
+	require.True(t, matched)
+	require.Equal(t, "post", v)
+	require.Equal(t, Params{{"name", "hello-world"}}, ps)
+
+	// the mismatch falls through to the route added with Any
+	v, ps, matched = router.Match(http.MethodGet, "/posts/Hello_World")
+	require.True(t, matched)
+	require.Equal(t, "any", v)
+	require.Equal(t, Params{{"id", "Hello_World"}}, ps)
+
+	_, err := router.URLFor("post", map[string]string{"name": "No Slug"})
+	require.EqualError(t, err, "param 'name' is not a valid slug")
+
+	pattern := NewPattern(WithConstraint("slug", isSlug))
+	pattern.Add("/posts/:name<slug>", "post")
+	_, _, matched = pattern.Match("/posts/hello")
+	require.True(t, matched)
+	_, _, matched = pattern.Match("/posts/Hello")
+	require.False(t, matched)
+
+	require.Panics(t, func() { New().GET("/posts/:name<slug>", "post") })
+	require.Panics(t, func() { NewPattern().Add("/posts/:name<slug>", "post") })
+	require.Panics(t, func() { WithConstraint("int", isSlug) })
+	require.Panics(t, func() { WithConstraint("a/b", isSlug) })
+	for _, name := range []string{"", "a.b", "a@b", "a(b", "a=b", "a?b", "a{b", "a\\b"} {
+		require.Panics(t, func() { WithConstraint(name, isSlug) }, name)
+	}
+	require.Panics(t, func() { WithConstraint("slug", nil) })
+
+	_, err = BuildParallel([]Route{{Method: http.MethodGet, Path: "/posts/:name<slug>", Value: "post"}}, 1)
+	require.Error(t, err)
+	r, err := BuildParallel([]Route{{Method: http.MethodGet, Path: "/posts/:name<slug>", Value: "post"}}, 1,
+		WithConstraint("slug", isSlug))
+	require.NoError(t, err)
+	_, _, matched = r.Match(http.MethodGet, "/posts/Hello")
+	require.False(t, matched)
+}
+
+func TestRouterConstraint(t *testing.T) {
+	router := New()
+	router.Constraint("slug", isSlug)
+	router.GET("/posts/:name@slug", "post").Name("post")
+	router.GET("/users/:id@int.json", "user")
+
+	v, ps, matched := router.Match(http.MethodGet, "/posts/hello-world")
+	require.True(t, matched)
+	require.Equal(t, "post", v)
+	require.Equal(t, Params{{"name", "hello-world"}}, ps)
+	_, _, matched = router.Match(http.MethodGet, "/posts/Hello")
+	require.False(t, matched)
+	_, ps, matched = router.Match(http.MethodGet, "/users/7.json")
+	require.True(t, matched)
+	require.Equal(t, Params{{"id", "7"}}, ps)
+	_, _, matched = router.Match(http.MethodGet, "/users/a.json")
+	require.False(t, matched)
+
+	_, err := router.URLFor("post", map[string]string{"name": "No Slug"})
+	require.EqualError(t, err, "param 'name' is not a valid slug")
+
+	// the constraints are not shared with clones
+	clone := router.Clone()
+	clone.Constraint("lower", isSlug)
+	require.Panics(t, func() { router.GET("/l/:x@lower", "l") })
+	clone.GET("/l/:x@lower", "l")
+
+	require.Panics(t, func() { New().Constraint("a.b", isSlug) })
+	require.Panics(t, func() { New().GET("/posts/:name@slug", "post") })
+	require.Panics(t, func() { New().GET("/posts/:name@", "post") })
+	require.Panics(t, func() { New().GET("/files/*path@int", "files") })
+	require.Panics(t, func() { New().GET("/user/:id<int>", "a").GET("/user/:id@int/x", "b") })
+}
+
+func TestRouterCustomConstraintMethods(t *testing.T) {
+	router := New(WithConstraint("slug", isSlug))
+	router.GET("/p/:id<slug>", "p")
+
+	v, ps, _ := router.Lookup(http.MethodGet, "/p/abc")
+	require.Equal(t, "p", v)
+	require.Equal(t, Params{{"id", "abc"}}, ps)
+	v, _, _ = router.Lookup(http.MethodGet, "/p/ABC")
+	require.Nil(t, v)
+
+	require.Equal(t, []string{http.MethodGet}, router.AllowedMethods("/p/abc"))
+	require.Empty(t, router.AllowedMethods("/p/ABC"))
+
+	_, _, err := router.MatchE(http.MethodPost, "/p/abc")
+	require.Equal(t, ErrMethodNotAllowed, err)
+	require.True(t, router.MatchResult(http.MethodPost, "/p/abc").MethodNotAllowed)
+}
@@ -0,0 +1,201 @@
+package wrmatch
+
+import "strings"
+
+// defaultRoute is a route added for a path with optional trailing segments,
+// which omits them and injects the params of their default values.
+type defaultRoute struct {
+	path   string
+	params Params
+}
+
+// splitDefaults parses the optional trailing named parameters of path,
+// which have a default value, e.g. "/posts/:page=1", or are marked with a
+// '?', e.g. "/users/:id?". It returns the path without the markers, e.g.
+// "/posts/:page", and the routes for the paths omitting the optional
+// segments, e.g. "/posts" with the param page=1. The params of optional
+// segments without a default are absent.
+// It panics if an optional parameter is followed by a segment which is not.
+func splitDefaults(path string) (string, []defaultRoute) {
+	segments := strings.Split(path, "/")
+	// defaults of the optional segments, nil if they have none
+	var defaults []*Param
+	first := -1
+	for i, segment := range segments {
+		if len(segment) > 1 && segment[0] == ':' {
+			var def *Param
+			optional := true
+			if eq := strings.IndexByte(segment, '='); eq > 0 {
+				name, _ := splitSuffix(segment[1:eq])
+				name, _ = splitConstraint(name)
+				def = &Param{name, segment[eq+1:]}
+				segments[i] = segment[:eq]
+			} else if segment[len(segment)-1] == '?' {
+				segments[i] = segment[:len(segment)-1]
+			} else {
+				optional = false
+			}
+			if optional {
+				if first < 0 {
+					first = i
+				}
+				defaults = append(defaults, def)
+				continue
+			}
+		}
+		if first >= 0 {
+			panic("optional parameters are only allowed as trailing segments in path '" + path + "'")
+		}
+	}
+	if first < 0 {
+		return path, nil
+	}
+
+	routes := make([]defaultRoute, 0, len(defaults))
+	for i := first; i < len(segments); i++ {
+		prefix := strings.Join(segments[:i], "/")
+		if prefix == "" {
+			prefix = "/"
+		}
+		var params Params
+		for _, def := range defaults[i-first:] {
+			if def != nil {
+				params = append(params, *def)
+			}
+		}
+		routes = append(routes, defaultRoute{prefix, params})
+	}
+	return strings.Join(segments, "/"), routes
+}
+
+// expandOptional expands the optional segments of path, which are enclosed
+// in parentheses and start with a '/', e.g. "/api(/v1)/users", into the
+// paths with and without each of them, e.g. "/api/users" and
+// "/api/v1/users". The path with all optional segments comes last.
+// It panics if a group is empty, nested or not closed.
+func expandOptional(path string) []string {
+	type group struct{ start, end int }
+	var groups []group
+	for i := 0; i < len(path); i++ {
+		if path[i] == '(' && i+1 < len(path) && path[i+1] == '/' {
+			end := strings.IndexByte(path[i+1:], ')')
+			if end < 0 {
+				panic("optional segment is not closed in path '" + path + "'")
+			}
+			end += i + 1
+			if strings.Contains(path[i+1:end], "(/") {
+				panic("optional segments must not be nested in path '" + path + "'")
+			}
+			if end == i+2 {
+				panic("optional segment must not be '/' only in path '" + path + "'")
+			}
+			groups = append(groups, group{i, end})
+			i = end
+		}
+	}
+	if len(groups) == 0 {
+		return []string{path}
+	}
+
+	paths := make([]string, 0, 1<<len(groups))
+	for mask := 0; mask < 1<<len(groups); mask++ {
+		var sb strings.Builder
+		prev := 0
+		for j, g := range groups {
+			sb.WriteString(path[prev:g.start])
+			if mask&(1<<j) != 0 {
+				sb.WriteString(path[g.start+1 : g.end])
+			}
+			prev = g.end + 1
+		}
+		sb.WriteString(path[prev:])
+		p := sb.String()
+		if p == "" {
+			p = "/"
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// expandRoutes returns the routes registered for path, the variants of its
+// optional segments and parameters, each with the params of the defaults
+// it omits. The route with all segments comes last. It returns the path
+// itself, if it has no optional parts.
+func expandRoutes(path string) []defaultRoute {
+	if strings.Contains(path, "(/") {
+		var routes []defaultRoute
+		for _, p := range expandOptional(path) {
+			routes = append(routes, expandRoutes(p)...)
+		}
+		checkVariants(path, routes)
+		return routes
+	}
+	if strings.ContainsAny(path, "=?") {
+		if full, routes := splitDefaults(path); len(routes) > 0 {
+			return append(routes, defaultRoute{path: full})
+		}
+	}
+	return []defaultRoute{{path: path}}
+}
+
+// routePaths returns the registered paths of the route added with path,
+// the variants of its optional parts with the anonymous wildcards named, see
+// expandRoutes. A path whose optional parts can not be expanded has none.
+func routePaths(path string) []string {
+	var routes []defaultRoute
+	if err := registrationError(func() { routes = expandRoutes(nameWildcards(path)) }); err != nil {
+		return nil
+	}
+	paths := make([]string, len(routes))
+	for i, route := range routes {
+		paths[i] = route.path
+	}
+	return paths
+}
+
+// checkVariants panics with a ConflictError, if the routes expanded from the
+// optional segments of path can not be registered together, e.g. for
+// "/docs(/:version)/page" the wildcard ':version' conflicts with the static
+// segment of "/docs/page". Nothing is registered in that case.
+func checkVariants(path string, routes []defaultRoute) {
+	root := new(node)
+	for _, route := range routes {
+		err := registrationError(func() { root.addRoute(route.path, route.path) })
+		if ce, ok := err.(*ConflictError); ok {
+			panic(&ConflictError{
+				Path:     path,
+				Existing: ce.Existing,
+				Segment:  ce.Segment,
+				msg: "the optional segments of path '" + path + "' expand to the conflicting paths '" +
+					route.path + "' and '" + ce.Existing + "': " + ce.msg,
+			})
+		}
+	}
+}
+
+// RouteParams returns the params added to the matches of the route
+// registered with the given method and path, which are not captured from the
+// path, e.g. the default values of the omitted optional parameters. The path
+// must be the registered pattern, not a request path.
+func (r *Router) RouteParams(method, path string) Params {
+	if n := r.findRoute(method, path); n != nil && n.meta != nil && len(n.meta.params) > 0 {
+		return append(Params(nil), n.meta.params...)
+	}
+	return nil
+}
+
+// SetRouteParams sets the params added to the matches of the route
+// registered with the given method and path, see RouteParams, e.g. to
+// restore the defaults of a route table. The path must be the registered
+// pattern, not a request path.
+// It reports whether a value was registered for the method and path.
+func (r *Router) SetRouteParams(method, path string, ps Params) bool {
+	r.checkSealed()
+	n := r.findRoute(method, path)
+	if n == nil {
+		return false
+	}
+	n.routeMeta().params = append(Params(nil), ps...)
+	return true
+}
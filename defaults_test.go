@@ -0,0 +1,198 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterDefaultParams(t *testing.T) {
+	router := New()
+	router.GET("/posts/:page=1", "posts").Name("posts")
+	router.GET("/archive/:year/:month=01/:day=01", "archive")
+
+	tests := []struct {
+		path   string
+		value  string
+		params Params
+	}{
+		{"/posts/3", "posts", Params{{"page", "3"}}},
+		{"/posts", "posts", Params{{"page", "1"}}},
+		{"/posts/", "posts", Params{{"page", "1"}}},
+		{"/archive/2021/03/04", "archive", Params{{"year", "2021"}, {"month", "03"}, {"day", "04"}}},
+		{"/archive/2021/03", "archive", Params{{"year", "2021"}, {"month", "03"}, {"day", "01"}}},
+		{"/archive/2021", "archive", Params{{"year", "2021"}, {"month", "01"}, {"day", "01"}}},
+	}
+	for _, tt := range tests {
+		v, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.True(t, matched, tt.path)
+		require.Equal(t, tt.value, v, tt.path)
+		require.Equal(t, tt.params, ps, tt.path)
+
+		if tt.path[len(tt.path)-1] != '/' {
+			v, ps, _ = router.Lookup(http.MethodGet, tt.path)
+			require.Equal(t, tt.value, v, tt.path)
+			require.Equal(t, tt.params, ps, tt.path)
+		}
+	}
+	_, _, matched := router.Match(http.MethodGet, "/archive")
+	require.False(t, matched)
+
+	url, err := router.URLFor("posts", map[string]string{"page": "2"})
+	require.NoError(t, err)
+	require.Equal(t, "/posts/2", url)
+
+	require.Equal(t, Params{{"month", "01"}, {"day", "01"}}, router.RouteParams(http.MethodGet, "/archive/:year"))
+	require.Nil(t, router.RouteParams(http.MethodGet, "/posts/:page"))
+	require.Nil(t, router.RouteParams(http.MethodGet, "/unknown"))
+
+	require.True(t, router.SetRouteParams(http.MethodGet, "/posts", Params{{"page", "0"}}))
+	require.False(t, router.SetRouteParams(http.MethodGet, "/unknown", Params{{"page", "0"}}))
+	_, ps, _ := router.Match(http.MethodGet, "/posts")
+	require.Equal(t, Params{{"page", "0"}}, ps)
+	require.True(t, router.SetRouteParams(http.MethodGet, "/posts", nil))
+	_, ps, _ = router.Match(http.MethodGet, "/posts")
+	require.Nil(t, ps)
+
+	require.Panics(t, func() { New().GET("/a/:b=1/c", "c") })
+	require.Panics(t, func() { New().GET("/a/:b=1/:c", "c") })
+	require.Panics(t, func() { New().GET("/a", "a").GET("/a/:b=1", "b") })
+}
+
+func TestSplitDefaults(t *testing.T) {
+	path, routes := splitDefaults("/:page=1")
+	require.Equal(t, "/:page", path)
+	require.Equal(t, []defaultRoute{{"/", Params{{"page", "1"}}}}, routes)
+
+	path, routes = splitDefaults("/a=b/:c")
+	require.Equal(t, "/a=b/:c", path)
+	require.Nil(t, routes)
+
+	path, routes = splitDefaults("/a/:b=")
+	require.Equal(t, "/a/:b", path)
+	require.Equal(t, []defaultRoute{{"/a", Params{{"b", ""}}}}, routes)
+
+	path, routes = splitDefaults("/a/:b<int>=1/:c?")
+	require.Equal(t, "/a/:b<int>/:c", path)
+	require.Equal(t, []defaultRoute{{"/a", Params{{"b", "1"}}}, {"/a/:b<int>", nil}}, routes)
+}
+
+func TestRouterOptionalParams(t *testing.T) {
+	router := New()
+	router.GET("/users/:id<int>?", "users")
+	router.GET("/archive/:year=2021/:month?", "archive")
+
+	tests := []struct {
+		path    string
+		value   string
+		params  Params
+		matched bool
+	}{
+		{"/users/42", "users", Params{{"id", "42"}}, true},
+		{"/users", "users", nil, true},
+		{"/users/gopher", "", nil, false},
+		{"/archive/2020/03", "archive", Params{{"year", "2020"}, {"month", "03"}}, true},
+		{"/archive/2020", "archive", Params{{"year", "2020"}}, true},
+		{"/archive", "archive", Params{{"year", "2021"}}, true},
+	}
+	for _, tt := range tests {
+		v, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.Equal(t, tt.matched, matched, tt.path)
+		if tt.matched {
+			require.Equal(t, tt.value, v, tt.path)
+			require.Equal(t, tt.params, ps, tt.path)
+		}
+	}
+
+	require.Panics(t, func() { New().GET("/a/:b?/c", "c") })
+	require.Panics(t, func() { New().GET("/a/:b?/:c", "c") })
+}
+
+func TestRouterOptionalSegments(t *testing.T) {
+	router := New()
+	router.GET("/api(/v1)/users(/:id)", "users").Name("users")
+	router.GET("/wiki/Go_(/lang)", "wiki")
+
+	for _, path := range []string{"/api/users", "/api/v1/users", "/api/users/1", "/api/v1/users/1"} {
+		v, _, matched := router.Match(http.MethodGet, path)
+		require.True(t, matched, path)
+		require.Equal(t, "users", v, path)
+	}
+	require.True(t, router.HasRoute(http.MethodGet, "/wiki/Go_"))
+	require.True(t, router.HasRoute(http.MethodGet, "/wiki/Go_/lang"))
+
+	url, err := router.URLFor("users", map[string]string{"id": "1"})
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/users/1", url)
+
+	// the variants "/docs/page" and "/docs/:version/page" can not coexist
+	router = New()
+	err = router.AddE(http.MethodGet, "/docs(/:version)/page", "page")
+	ce, ok := err.(*ConflictError)
+	require.True(t, ok, err)
+	require.Equal(t, "/docs(/:version)/page", ce.Path)
+	require.Equal(t, "/docs/page", ce.Existing)
+	require.EqualError(t, err, "the optional segments of path '/docs(/:version)/page' expand to the conflicting "+
+		"paths '/docs/:version/page' and '/docs/page': wildcard segment ':version' conflicts with existing "+
+		"children in path '/docs/:version/page'")
+	require.Empty(t, router.Routes())
+	require.PanicsWithError(t, err.Error(), func() { New().GET("/docs(/:version)/page", "page") })
+	_, err = BuildParallel([]Route{{Method: http.MethodGet, Path: "/docs(/:version)/page", Value: "page"}}, 2)
+	require.Error(t, err)
+	require.PanicsWithError(t, err.Error(), func() { NewPattern().Add("/docs(/:version)/page", "page") })
+
+	require.Panics(t, func() { New().GET("/a(/b", "b") })
+	require.Panics(t, func() { New().GET("/a(/b(/c))", "c") })
+	require.Panics(t, func() { New().GET("/a(/)", "a") })
+}
+
+func TestRouterOptionalRoute(t *testing.T) {
+	router := New()
+	router.GET("/api(/v1)/users(/:id)", "users")
+	router.GET("/archive/:year=2021/:month?", "archive")
+	paths := []string{"/api/users", "/api/v1/users/1", "/archive", "/archive/2020/03"}
+
+	// the paths as given to Add stand for all their variants
+	require.True(t, router.HasRoute(http.MethodGet, "/api(/v1)/users(/:id)"))
+	require.True(t, router.HasRoute(http.MethodGet, "/archive/:year=2021/:month?"))
+	require.True(t, router.Disable(http.MethodGet, "/api(/v1)/users(/:id)"))
+	require.True(t, router.Disable(http.MethodGet, "/archive/:year=2021/:month?"))
+	require.True(t, router.IsDisabled(http.MethodGet, "/api(/v1)/users(/:id)"))
+	for _, path := range paths {
+		_, _, matched := router.Match(http.MethodGet, path)
+		require.False(t, matched, path)
+	}
+	require.True(t, router.Enable(http.MethodGet, "/api(/v1)/users(/:id)"))
+	require.True(t, router.Enable(http.MethodGet, "/archive/:year=2021/:month?"))
+	require.True(t, router.Update(http.MethodGet, "/api(/v1)/users(/:id)", "users2"))
+	for _, path := range paths[:2] {
+		v, _, matched := router.Match(http.MethodGet, path)
+		require.True(t, matched, path)
+		require.Equal(t, "users2", v, path)
+	}
+
+	require.True(t, router.Remove(http.MethodGet, "/api(/v1)/users(/:id)"))
+	require.True(t, router.Remove(http.MethodGet, "/archive/:year=2021/:month?"))
+	require.Empty(t, router.Routes())
+	require.Equal(t, uint16(0), router.maxParams)
+	require.False(t, router.HasRoute(http.MethodGet, "/api(/v1)/users(/:id)"))
+	require.False(t, router.Remove(http.MethodGet, "/api(/v1)/users(/:id)"))
+	require.False(t, router.HasRoute(http.MethodGet, "/a(/b"))
+
+	pattern := NewPattern()
+	pattern.Add("/api(/v1)/users(/:id)", "users")
+	require.True(t, pattern.Update("/api(/v1)/users(/:id)", "users2"))
+	v, _, matched := pattern.Match("/api/v1/users/1")
+	require.True(t, matched)
+	require.Equal(t, "users2", v)
+	require.True(t, pattern.Remove("/api(/v1)/users(/:id)"))
+	require.Empty(t, pattern.List())
+}
+
+func TestExpandOptional(t *testing.T) {
+	require.Equal(t, []string{"/a"}, expandOptional("/a"))
+	require.Equal(t, []string{"/", "/a"}, expandOptional("(/a)"))
+	require.Equal(t, []string{"/a/d", "/a/b/d", "/a/c/d", "/a/b/c/d"}, expandOptional("/a(/b)(/c)/d"))
+	require.Equal(t, []string{"/x(y)"}, expandOptional("/x(y)"))
+}
@@ -0,0 +1,125 @@
+package wrmatch
+
+import "strings"
+
+// segmentEntry is the subtree of a static first path segment, skip is the
+// length of the path prefix consumed by the nodes above n.
+type segmentEntry struct {
+	n    *node
+	skip int
+}
+
+// segmentIndex maps the static first path segments of a tree to their subtree.
+type segmentIndex map[string]segmentEntry
+
+// WithFirstSegmentIndex indexes the static first path segment of the routes
+// in a hash map per method, so that matching jumps directly to the subtree of
+// the segment instead of scanning the common prefixes at the root.
+// It pays off for wide route tables with many top-level prefixes.
+// Default: disable
+func WithFirstSegmentIndex() Option {
+	return func(r *Options) {
+		r.firstSegmentIndex = true
+	}
+}
+
+// firstSegment returns the first segment of path, without the leading '/'.
+func firstSegment(path string) string {
+	path = path[1:]
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// staticSegment returns the first segment of the route path and whether it
+// can be indexed, i.e. contains no wildcard.
+func staticSegment(path string) (string, bool) {
+	seg := firstSegment(path)
+	return seg, seg != "" && !strings.ContainsAny(seg, ":*")
+}
+
+// newSegmentIndex builds the segment index of the tree root.
+func newSegmentIndex(root *node) segmentIndex {
+	idx := make(segmentIndex)
+	root.walk("", func(path string, _ *node) bool {
+		if seg, ok := staticSegment(path); ok {
+			if _, ok := idx[seg]; !ok {
+				idx[seg] = root.segmentEntry("/" + seg)
+			}
+		}
+		return true
+	})
+	return idx
+}
+
+// segmentEntry returns the deepest node at which a lookup of every path
+// starting with prefix can start. Only static nodes are consumed and at least
+// one byte of prefix is left, so the lookup from the entry walks the same
+// nodes as a lookup from n.
+func (n *node) segmentEntry(prefix string) segmentEntry {
+	skip := 0
+	for {
+		end := skip + len(n.path)
+		if n.wildChild || n.nType == param || end >= len(prefix) || prefix[skip:end] != n.path {
+			return segmentEntry{n, skip}
+		}
+		var next *node
+		for i, c := range []byte(n.indices) {
+			if c == prefix[end] {
+				next = n.children[i]
+				break
+			}
+		}
+		if next == nil {
+			return segmentEntry{n, skip}
+		}
+		n, skip = next, end
+	}
+}
+
+// reindex rebuilds the segment index of the method tree, if enabled.
+func (r *Router) reindex(method string) {
+	if !r.firstSegmentIndex {
+		return
+	}
+	root := r.trees[method]
+	if root == nil {
+		delete(r.segments, method)
+		return
+	}
+	if r.segments == nil {
+		r.segments = make(map[string]segmentIndex)
+	}
+	r.segments[method] = newSegmentIndex(root)
+}
+
+// indexRoute adds the first segment of the route path, which was added to
+// the method tree, to the segment index, if enabled. Only the entry of the
+// segment is updated: adding a route splits the nodes in place, so the
+// entries of the other segments stay valid, although an entry at a split
+// node is one node above the deepest one now.
+func (r *Router) indexRoute(method, path string) {
+	if !r.firstSegmentIndex {
+		return
+	}
+	idx := r.segments[method]
+	if idx == nil {
+		r.reindex(method)
+		return
+	}
+	if seg, ok := staticSegment(path); ok {
+		idx[seg] = r.trees[method].segmentEntry("/" + seg)
+	}
+}
+
+// lookup looks up path in the tree root of method, starting at the subtree
+// of the first path segment if it is indexed.
+func (r *Router) lookup(method string, root *node, path string, params func() *Params, view *ParamsView) (*node, *Params, bool) {
+	if idx := r.segments[method]; idx != nil && len(path) > 0 && path[0] == '/' {
+		if e, ok := idx[firstSegment(path)]; ok {
+			return e.n.lookupFrom(path, e.skip, params, view, &r.Options)
+		}
+	}
+	return root.lookupFrom(path, 0, params, view, &r.Options)
+}
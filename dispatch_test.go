@@ -0,0 +1,104 @@
+package wrmatch
+
+import (
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirstSegmentIndex(t *testing.T) {
+	routes := []string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/cmd/:tool/",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/doc/",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/info/:user/public",
+		"/info/:user/project/:project",
+		"/uploads",
+		"/users/:id",
+	}
+	plain := New()
+	indexed := New(WithFirstSegmentIndex())
+	for _, route := range routes {
+		plain.GET(route, route)
+		indexed.GET(route, route)
+	}
+
+	paths := []string{
+		"/", "/cmd/test/", "/cmd/test", "/cmd/test/3", "/src/", "/src/some/file.png",
+		"/search/", "/search/someth!ng+in+ünìcodé", "/search", "/user_gopher",
+		"/user_gopher/about", "/files/js/inc/framework.js", "/info/gordon/public",
+		"/info/gordon/project/go", "/uploads", "/uploads/", "/users/42", "/users",
+		"/DOC/GO_FAQ.HTML", "/doc/../doc/go1.html", "/notfound", "/u", "",
+	}
+	for _, path := range paths {
+		v1, ps1, matched1 := plain.Match(http.MethodGet, path)
+		v2, ps2, matched2 := indexed.Match(http.MethodGet, path)
+		require.Equal(t, matched1, matched2, path)
+		require.Equal(t, v1, v2, path)
+		require.Equal(t, ps1, ps2, path)
+
+		_, view1, _ := plain.MatchView(http.MethodGet, path)
+		_, view2, _ := indexed.MatchView(http.MethodGet, path)
+		require.Equal(t, view1.Params(), view2.Params(), path)
+	}
+
+	require.True(t, indexed.Remove(http.MethodGet, "/uploads"))
+	_, _, matched := indexed.Match(http.MethodGet, "/uploads")
+	require.False(t, matched)
+	v, _, matched := indexed.Match(http.MethodGet, "/users/42")
+	require.True(t, matched)
+	require.Equal(t, "/users/:id", v)
+}
+
+func TestFirstSegmentIndexIncremental(t *testing.T) {
+	// the routes split the nodes of each other's segments in every order
+	routes := []string{
+		"/users/:id", "/users", "/user", "/usa/:state", "/u", "/us/", "/uploads/*path",
+		"/a/b/c", "/a/b", "/ab/c", "/abc", "/abcd/:x", "/b", "/bb/:id/cc", "/bbb",
+	}
+	paths := append([]string{
+		"/users/1", "/user/", "/usa/ny", "/us", "/uploads/a/b", "/a/b/c/", "/ab/c",
+		"/abcd/1", "/bb/1/cc", "/bbb", "/USERS/1", "/x", "/abcde",
+	}, routes...)
+
+	for seed := int64(0); seed < 20; seed++ {
+		order := append([]string(nil), routes...)
+		rand.New(rand.NewSource(seed)).Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+		plain := New()
+		indexed := New(WithFirstSegmentIndex())
+		for _, route := range order {
+			plain.GET(route, route)
+			indexed.GET(route, route)
+
+			for _, path := range paths {
+				v1, ps1, matched1 := plain.Match(http.MethodGet, path)
+				v2, ps2, matched2 := indexed.Match(http.MethodGet, path)
+				require.Equal(t, matched1, matched2, "seed %d %s", seed, path)
+				require.Equal(t, v1, v2, "seed %d %s", seed, path)
+				require.Equal(t, ps1, ps2, "seed %d %s", seed, path)
+			}
+		}
+
+		// the incrementally updated entries are valid, but may be above the
+		// entries of a rebuilt index
+		idx := indexed.segments[http.MethodGet]
+		rebuilt := newSegmentIndex(indexed.trees[http.MethodGet])
+		require.Len(t, idx, len(rebuilt))
+		for seg, e := range rebuilt {
+			require.LessOrEqual(t, idx[seg].skip, e.skip, seg)
+		}
+	}
+}
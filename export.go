@@ -0,0 +1,88 @@
+package wrmatch
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// exportedRoute is a route of an exported route table.
+type exportedRoute struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Value is encoded with the ValueCodec of the router.
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+	Name     string `json:"name,omitempty"`
+	// Params added to the matches of the route, see Router.RouteParams.
+	Params Params `json:"params,omitempty"`
+}
+
+// Export writes the registered routes as JSON to w, in the order of
+// Router.Routes, including their disabled state, name and the params added
+// to their matches, e.g. the defaults of optional parameters. The values are
+// encoded with the ValueCodec, see WithValueCodec. The routes of the
+// optional parts of a path are exported separately.
+// Overlays are not included.
+func (r *Router) Export(w io.Writer) error {
+	routes := r.Routes()
+	exported := make([]exportedRoute, 0, len(routes))
+	codec := r.ValueCodec()
+	for _, route := range routes {
+		value, err := codec.Encode(route.Value)
+		if err != nil {
+			return &RouteError{route, err}
+		}
+		exported = append(exported, exportedRoute{
+			Method:   route.Method,
+			Path:     route.Path,
+			Value:    string(value),
+			Disabled: route.Disabled,
+			Name:     route.Name,
+			Params:   r.RouteParams(route.Method, route.Path),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(exported)
+}
+
+// Import registers the routes written by Export, the values are decoded
+// with the ValueCodec, see WithValueCodec. Like AddAll, it registers all
+// valid routes and returns a RouteErrors listing every route which could
+// not be decoded, registered or named.
+func (r *Router) Import(rd io.Reader) error {
+	var exported []exportedRoute
+	if err := json.NewDecoder(rd).Decode(&exported); err != nil {
+		return err
+	}
+	codec := r.ValueCodec()
+	var errs RouteErrors
+	for _, er := range exported {
+		route := Route{Method: er.Method, Path: er.Path, Disabled: er.Disabled, Name: er.Name}
+		value, err := codec.Decode([]byte(er.Value))
+		if err == nil {
+			route.Value = value
+			// the paths are registered paths, so they are not converted
+			err = r.addE(route.Method, func() { r.add(route.Method, route.Path, value) })
+		}
+		if err == nil {
+			if len(er.Params) > 0 {
+				r.SetRouteParams(route.Method, route.Path, er.Params)
+			}
+			if route.Disabled {
+				r.Disable(route.Method, route.Path)
+			}
+			if route.Name != "" {
+				err = registrationError(func() { r.setName(route.Method, route.Path, route.Name) })
+			}
+		}
+		if err != nil {
+			errs = append(errs, &RouteError{route, err})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package wrmatch
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterExportImport(t *testing.T) {
+	router := New(WithSaveMatchedRoutePath())
+	router.GET("/user/:id<int>", "user").Name("user")
+	router.GET("/posts/:page=1", "posts")
+	router.GET("/orgs/:org/*/settings", "settings")
+	router.POST("/v1/projects/\\:undelete", "undelete")
+	router.Any("/files/**/meta.json", "meta")
+	router.Disable(http.MethodPost, "/v1/projects/\\:undelete")
+
+	var buf bytes.Buffer
+	require.NoError(t, router.Export(&buf))
+	require.Contains(t, buf.String(), `"path": "/posts",`)
+	require.Contains(t, buf.String(), `"params": {
+      "page": "1"
+    }`)
+	require.Contains(t, buf.String(), `"path": "/user/:id<int>",`)
+
+	imported := New(WithSaveMatchedRoutePath())
+	require.NoError(t, imported.Import(bytes.NewReader(buf.Bytes())))
+	require.Equal(t, router.Routes(), imported.Routes())
+	for _, req := range []struct{ method, path string }{
+		{http.MethodGet, "/user/1"}, {http.MethodGet, "/posts"}, {http.MethodGet, "/posts/2"},
+		{http.MethodGet, "/orgs/go/x/settings"}, {http.MethodPost, "/v1/projects/:undelete"},
+		{http.MethodPut, "/files/a/b/meta.json"},
+	} {
+		v1, ps1, matched1 := router.Match(req.method, req.path)
+		v2, ps2, matched2 := imported.Match(req.method, req.path)
+		require.Equal(t, matched1, matched2, req.path)
+		require.Equal(t, v1, v2, req.path)
+		require.Equal(t, ps1, ps2, req.path)
+	}
+	url, err := imported.URLFor("user", map[string]string{"id": "7"})
+	require.NoError(t, err)
+	require.Equal(t, "/user/7", url)
+
+	// the export does not depend on the registration order
+	var again bytes.Buffer
+	require.NoError(t, imported.Export(&again))
+	require.Equal(t, buf.String(), again.String())
+
+	// the paths are not converted again
+	mux := New(WithServeMuxPatterns())
+	require.NoError(t, mux.Import(bytes.NewReader(buf.Bytes())))
+	require.Equal(t, router.Routes(), mux.Routes())
+
+	err = New().Import(strings.NewReader(`[{"method": "GET", "path": "/a", "value": "a"}, {"method": "GET", "path": "/a", "value": "b"}]`))
+	var errs RouteErrors
+	require.True(t, errors.As(err, &errs))
+	require.Len(t, errs, 1)
+	require.Equal(t, "b", errs[0].Route.Value)
+	require.Error(t, New().Import(strings.NewReader(`{`)))
+}
+
+func TestRouterExportCodec(t *testing.T) {
+	type backend struct {
+		Host string `json:"host"`
+	}
+	router := New(WithValueCodec(JSONCodec{New: func() interface{} { return new(backend) }}))
+	router.GET("/a", &backend{"a.local"})
+
+	var buf bytes.Buffer
+	require.NoError(t, router.Export(&buf))
+	require.Contains(t, buf.String(), `"value": "{\"host\":\"a.local\"}"`)
+
+	imported := New(WithValueCodec(JSONCodec{New: func() interface{} { return new(backend) }}))
+	require.NoError(t, imported.Import(&buf))
+	v, _, matched := imported.Match(http.MethodGet, "/a")
+	require.True(t, matched)
+	require.Equal(t, &backend{"a.local"}, v)
+
+	// the default string codec only encodes strings
+	router = New()
+	router.GET("/a", 1)
+	var rerr *RouteError
+	require.True(t, errors.As(router.Export(&buf), &rerr))
+	require.Equal(t, "/a", rerr.Route.Path)
+}
@@ -0,0 +1,279 @@
+// Package fuzz provides helpers to check the matching of a wrmatch.Router
+// against a slow, but obviously correct reference matcher. It is meant to be
+// used from fuzz tests, e.g.
+//
+//  func FuzzRouter(f *testing.F) {
+//      router := newRouter()
+//      f.Fuzz(func(t *testing.T, path string) {
+//          if err := fuzz.CheckConsistency(router, http.MethodGet, path); err != nil {
+//              t.Fatal(err)
+//          }
+//      })
+//  }
+//
+// Only exact matches are compared, i.e. without trailing slash or fixed
+// path redirection.
+package fuzz
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/wyy-go/wrmatch"
+)
+
+// Reference is a reference matcher, it matches a path against every
+// registered route one by one.
+type Reference struct {
+	routes            []route
+	anyMethods        []string
+	trimCatchAllSlash bool
+}
+
+// route is a registered route with the params added to its matches.
+type route struct {
+	wrmatch.Route
+	params wrmatch.Params
+}
+
+// NewReference returns a reference matcher for the enabled routes of r.
+// Overlays are not included.
+func NewReference(r *wrmatch.Router) *Reference {
+	ref := &Reference{anyMethods: r.AnyMethods(), trimCatchAllSlash: r.TrimCatchAllSlash()}
+	for _, rt := range r.Routes() {
+		if !rt.Disabled {
+			ref.routes = append(ref.routes, route{rt, r.RouteParams(rt.Method, rt.Path)})
+		}
+	}
+	return ref
+}
+
+// Match returns the registered path of the route matching method and path
+// exactly and the url params, followed by the params added for the route,
+// e.g. the defaults of optional parameters. ok is false if no route matches.
+// The routes added with Any are consulted after the routes of the method.
+func (ref *Reference) Match(method, path string) (route string, ps wrmatch.Params, ok bool) {
+	if route, ps, ok = ref.match(method, path); ok {
+		return route, ps, ok
+	}
+	for _, m := range ref.anyMethods {
+		if m == method {
+			return ref.match(wrmatch.MethodAny, path)
+		}
+	}
+	return "", nil, false
+}
+
+func (ref *Reference) match(method, path string) (route string, ps wrmatch.Params, ok bool) {
+	for _, rt := range ref.routes {
+		if rt.Method != method {
+			continue
+		}
+		if ps, ok := ref.matchTemplate(rt.Path, path); ok {
+			return rt.Path, append(ps, rt.params...), true
+		}
+	}
+	return "", nil, false
+}
+
+// matchTemplate matches path against the registered path template.
+func (ref *Reference) matchTemplate(template, path string) (wrmatch.Params, bool) {
+	var ps wrmatch.Params
+	for template != "" {
+		switch template[0] {
+		case '\\':
+			// an escaped ':' or '*' is literal
+			if path == "" || path[0] != template[1] {
+				return nil, false
+			}
+			template, path = template[2:], path[1:]
+
+		case ':':
+			name := template[1:]
+			if i := strings.IndexByte(name, '/'); i >= 0 {
+				name = name[:i]
+			}
+			template = template[1+len(name):]
+
+			value := path
+			if i := strings.IndexByte(path, '/'); i >= 0 {
+				value = path[:i]
+			}
+			// like the router, an empty value only matches in front of a '/'
+			if value == "" && path == "" {
+				return nil, false
+			}
+			path = path[len(value):]
+
+			// the literal suffix follows the constraint, e.g. "id<int>.json"
+			if i := strings.IndexByte(name, '.'); i >= 0 {
+				suffix := name[i:]
+				if len(value) <= len(suffix) || !strings.HasSuffix(value, suffix) {
+					return nil, false
+				}
+				name, value = name[:i], value[:len(value)-len(suffix)]
+			}
+			var constraint string
+			if name, constraint = splitConstraint(name); constraint != "" && !satisfies(constraint, value) {
+				return nil, false
+			}
+			ps = append(ps, wrmatch.Param{Key: name, Value: value})
+
+		case '*':
+			// the catch-all value includes the '/' in front of it
+			return nil, false
+
+		default:
+			if strings.HasPrefix(template, "/*") {
+				return ref.matchCatchAll(template[2:], path, ps)
+			}
+			if path == "" || path[0] != template[0] {
+				return nil, false
+			}
+			template, path = template[1:], path[1:]
+		}
+	}
+	return ps, path == ""
+}
+
+// matchCatchAll matches path against the catch-all of a template, given by
+// its name followed by the fixed suffix, if any, e.g. "path/meta.json". A
+// catch-all followed by a suffix takes the most segments, at least one.
+func (ref *Reference) matchCatchAll(template, path string, ps wrmatch.Params) (wrmatch.Params, bool) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, false
+	}
+	name, suffix := template, ""
+	if i := strings.IndexByte(template, '/'); i >= 0 {
+		name, suffix = template[:i], template[i:]
+	}
+	if suffix == "" {
+		return append(ps, wrmatch.Param{Key: name, Value: ref.catchAllValue(path)}), true
+	}
+	for end := len(path) - 1; end > 1; end-- {
+		if path[end] != '/' {
+			continue
+		}
+		if rest, ok := ref.matchTemplate(suffix, path[end:]); ok {
+			ps = append(ps, wrmatch.Param{Key: name, Value: ref.catchAllValue(path[:end])})
+			return append(ps, rest...), true
+		}
+	}
+	return nil, false
+}
+
+// catchAllValue returns the value of a catch-all matching path.
+func (ref *Reference) catchAllValue(path string) string {
+	if ref.trimCatchAllSlash {
+		return path[1:]
+	}
+	return path
+}
+
+// constraints are the expressions of the built-in constraints.
+var constraints = map[string]*regexp.Regexp{
+	"int":   regexp.MustCompile(`^-?[0-9]+$`),
+	"uuid":  regexp.MustCompile(`^[0-9A-Fa-f]{8}(-[0-9A-Fa-f]{4}){3}-[0-9A-Fa-f]{12}$`),
+	"alpha": regexp.MustCompile(`^[A-Za-z]+$`),
+	"hex":   regexp.MustCompile(`^[0-9A-Fa-f]+$`),
+	"date":  regexp.MustCompile(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`),
+}
+
+// satisfies reports whether value satisfies the built-in constraint.
+func satisfies(constraint, value string) bool {
+	if !constraints[constraint].MatchString(value) {
+		return false
+	}
+	if constraint == "date" {
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	}
+	return true
+}
+
+// check returns an error if a route uses a feature the reference does not
+// support, i.e. a custom constraint.
+func (ref *Reference) check() error {
+	for _, rt := range ref.routes {
+		for p := rt.Path; ; {
+			i := strings.IndexByte(p, ':')
+			if i < 0 {
+				break
+			}
+			escaped := i > 0 && p[i-1] == '\\'
+			name := p[i+1:]
+			p = name
+			if escaped {
+				// an escaped ':' is literal
+				continue
+			}
+			if j := strings.IndexAny(name, "/."); j >= 0 {
+				name = name[:j]
+			}
+			if _, constraint := splitConstraint(name); constraint != "" && constraints[constraint] == nil {
+				return fmt.Errorf("%s %q: the custom constraint %q is not supported by the reference matcher",
+					rt.Method, rt.Path, constraint)
+			}
+		}
+	}
+	return nil
+}
+
+// splitConstraint splits the name of a named parameter with a constraint,
+// e.g. "id<int>" or "id@int", into the name and the constraint. The
+// constraint is empty, if there is none.
+func splitConstraint(name string) (string, string) {
+	if i := strings.IndexByte(name, '<'); i >= 0 {
+		return name[:i], name[i+1 : len(name)-1]
+	}
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// CheckConsistency matches method and path with r and with the reference
+// matcher of r and returns an error describing the difference, if the
+// results differ. It returns an error as well, if a route of r has a custom
+// constraint, which the reference can not check.
+func CheckConsistency(r *wrmatch.Router, method, path string) error {
+	ref := NewReference(r)
+	if err := ref.check(); err != nil {
+		return err
+	}
+	wantRoute, wantParams, wantOK := ref.Match(method, path)
+	wantParams = userParams(wantParams)
+
+	res := r.MatchRedirect(method, path)
+	params := userParams(res.Params)
+
+	if res.Matched != wantOK || res.Route != wantRoute {
+		return fmt.Errorf("%s %q: router matched %v route %q, reference matched %v route %q",
+			method, path, res.Matched, res.Route, wantOK, wantRoute)
+	}
+	if len(params) != len(wantParams) {
+		return fmt.Errorf("%s %q: router params %v, reference params %v", method, path, params, wantParams)
+	}
+	for i := range params {
+		if params[i] != wantParams[i] {
+			return fmt.Errorf("%s %q: router params %v, reference params %v", method, path, params, wantParams)
+		}
+	}
+	return nil
+}
+
+// userParams returns the params without the ones added by the router
+// itself, which the reference does not know about.
+func userParams(ps wrmatch.Params) wrmatch.Params {
+	var params wrmatch.Params
+	for _, p := range ps {
+		switch p.Key {
+		case wrmatch.MatchedRoutePathParam, wrmatch.LocaleParam, wrmatch.VersionParam:
+		default:
+			params = append(params, p)
+		}
+	}
+	return params
+}
@@ -0,0 +1,40 @@
+//go:build go1.18
+// +build go1.18
+
+package fuzz
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/wyy-go/wrmatch"
+)
+
+func FuzzCheckConsistency(f *testing.F) {
+	for _, path := range testPaths {
+		f.Add(path)
+	}
+	router := newTestRouter()
+	f.Fuzz(func(t *testing.T, path string) {
+		if err := CheckConsistency(router, http.MethodGet, path); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzCheckConsistencySyntax(f *testing.F) {
+	var routers []*wrmatch.Router
+	for _, tt := range syntaxTests {
+		for _, path := range tt.paths {
+			f.Add(path)
+		}
+		routers = append(routers, tt.router())
+	}
+	f.Fuzz(func(t *testing.T, path string) {
+		for i, router := range routers {
+			if err := CheckConsistency(router, http.MethodGet, path); err != nil {
+				t.Fatal(syntaxTests[i].name, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package fuzz
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wyy-go/wrmatch"
+)
+
+var testRoutes = []string{
+	"/",
+	"/cmd/:tool/:sub",
+	"/cmd/:tool/",
+	"/src/*filepath",
+	"/search/",
+	"/search/:query",
+	"/user_:name",
+	"/user_:name/about",
+	"/files/:dir/*filepath",
+	"/doc/",
+	"/doc/go_faq.html",
+	"/doc/go1.html",
+	"/info/:user/public",
+	"/info/:user/project/:project",
+}
+
+var testPaths = []string{
+	"/", "/cmd/test/", "/cmd/test", "/cmd/test/3", "/src/", "/src/some/file.png",
+	"/search/", "/search/someth!ng+in+ünìcodé", "/search/someth!ng+in+ünìcodé/",
+	"/user_gopher", "/user_gopher/about", "/files/js/inc/framework.js",
+	"/info/gordon/public", "/info/gordon/project/go", "/info/gordon/project/",
+	"/DOC/", "/doc", "", "/user_", "/src", "//", "/files/js", "/files//", "/user_/about", "/any/1",
+}
+
+func newTestRouter() *wrmatch.Router {
+	router := wrmatch.New(wrmatch.WithSaveMatchedRoutePath())
+	for _, route := range testRoutes {
+		router.GET(route, route)
+	}
+	router.Disable(http.MethodGet, "/doc/go1.html")
+	router.Any("/any/:id", "any")
+	return router
+}
+
+func TestReference(t *testing.T) {
+	ref := NewReference(newTestRouter())
+
+	route, ps, ok := ref.Match(http.MethodGet, "/files/js/inc/framework.js")
+	require.True(t, ok)
+	require.Equal(t, "/files/:dir/*filepath", route)
+	require.Equal(t, wrmatch.Params{{Key: "dir", Value: "js"}, {Key: "filepath", Value: "/inc/framework.js"}}, ps)
+
+	_, _, ok = ref.Match(http.MethodGet, "/doc/go1.html")
+	require.False(t, ok)
+	_, _, ok = ref.Match(http.MethodPost, "/")
+	require.False(t, ok)
+}
+
+func TestCheckConsistency(t *testing.T) {
+	router := newTestRouter()
+	for _, path := range append(testPaths, testRoutes...) {
+		require.NoError(t, CheckConsistency(router, http.MethodGet, path), path)
+	}
+}
+
+// syntaxTests are routers using the extended route syntax, with paths to
+// check them.
+var syntaxTests = []struct {
+	name   string
+	router func() *wrmatch.Router
+	paths  []string
+}{
+	{
+		name: "constraints",
+		router: func() *wrmatch.Router {
+			router := wrmatch.New()
+			router.GET("/user/:id<int>", "user")
+			router.GET("/day/:date<date>/:kind<alpha>", "day")
+			router.GET("/obj/:id<uuid>", "obj")
+			router.GET("/color/:c<hex>", "color")
+			router.GET("/at/:id@int.json", "at")
+			return router
+		},
+		paths: []string{
+			"/user/42", "/user/-7", "/user/ab", "/user/", "/user/4a", "/day/2024-02-29/x", "/day/2023-02-29/x",
+			"/day/2024-01-01/1", "/obj/123e4567-e89b-12d3-a456-426614174000", "/obj/123e4567", "/color/fF0", "/color/g",
+			"/at/1.json", "/at/a.json",
+		},
+	},
+	{
+		name: "suffix",
+		router: func() *wrmatch.Router {
+			router := wrmatch.New()
+			router.GET("/user/:id.json", "json")
+			router.GET("/post/:id<int>.xml", "xml")
+			return router
+		},
+		paths: []string{"/user/1.json", "/user/.json", "/user/1.jsonx", "/user/a.b.json", "/post/1.xml", "/post/a.xml"},
+	},
+	{
+		name: "catch-all suffix",
+		router: func() *wrmatch.Router {
+			router := wrmatch.New()
+			router.GET("/files/**/meta.json", "meta")
+			router.GET("/blobs/*path/raw", "raw")
+			router.GET("/orgs/:org/*/settings", "settings")
+			return router
+		},
+		paths: []string{
+			"/files/a/meta.json", "/files/a/b/meta.json", "/files/meta.json", "/files//meta.json",
+			"/files/a/meta.json/meta.json", "/blobs/a/raw/raw", "/blobs/raw", "/orgs/go/x/settings", "/orgs/go/settings",
+		},
+	},
+	{
+		name: "escapes",
+		router: func() *wrmatch.Router {
+			router := wrmatch.New()
+			router.GET("/posts/:id/\\:undelete", "undelete")
+			router.GET("/glob/\\*", "glob")
+			return router
+		},
+		paths: []string{"/posts/1/:undelete", "/posts/1/undelete", "/glob/*", "/glob/x"},
+	},
+	{
+		name: "defaults",
+		router: func() *wrmatch.Router {
+			router := wrmatch.New(wrmatch.WithSaveMatchedRoutePath())
+			router.GET("/posts/:page=1", "posts")
+			router.GET("/tags/:tag=all/:page=1", "tags")
+			router.GET("/users/:id?", "users")
+			return router
+		},
+		paths: []string{"/posts", "/posts/2", "/tags", "/tags/go", "/tags/go/3", "/users", "/users/1"},
+	},
+	{
+		name: "trim catch-all slash",
+		router: func() *wrmatch.Router {
+			router := wrmatch.New(wrmatch.WithTrimCatchAllSlash())
+			router.GET("/src/*filepath", "src")
+			router.GET("/files/**/meta.json", "meta")
+			return router
+		},
+		paths: []string{"/src/", "/src/a/b", "/files/a/b/meta.json"},
+	},
+}
+
+func TestReferenceSyntax(t *testing.T) {
+	router := wrmatch.New(wrmatch.WithTrimCatchAllSlash())
+	router.GET("/files/**/meta.json", "meta")
+	router.GET("/posts/:id<int>.json", "post")
+	router.GET("/tags/:page=1", "tags")
+	ref := NewReference(router)
+
+	route, ps, ok := ref.Match(http.MethodGet, "/files/a/meta.json/meta.json")
+	require.True(t, ok)
+	require.Equal(t, "/files/*$1/meta.json", route)
+	require.Equal(t, wrmatch.Params{{Key: "$1", Value: "a/meta.json"}}, ps)
+
+	route, ps, ok = ref.Match(http.MethodGet, "/posts/7.json")
+	require.True(t, ok)
+	require.Equal(t, "/posts/:id<int>.json", route)
+	require.Equal(t, wrmatch.Params{{Key: "id", Value: "7"}}, ps)
+	_, _, ok = ref.Match(http.MethodGet, "/posts/ab.json")
+	require.False(t, ok)
+
+	route, ps, ok = ref.Match(http.MethodGet, "/tags")
+	require.True(t, ok)
+	require.Equal(t, "/tags", route)
+	require.Equal(t, wrmatch.Params{{Key: "page", Value: "1"}}, ps)
+}
+
+func TestCheckConsistencySyntax(t *testing.T) {
+	for _, tt := range syntaxTests {
+		router := tt.router()
+		for _, path := range tt.paths {
+			require.NoError(t, CheckConsistency(router, http.MethodGet, path), tt.name+" "+path)
+		}
+	}
+
+	router := wrmatch.New(wrmatch.WithConstraint("slug", func(value string) bool { return true }))
+	router.GET("/posts/:name<slug>", "post")
+	require.EqualError(t, CheckConsistency(router, http.MethodGet, "/posts/a"),
+		`GET "/posts/:name<slug>": the custom constraint "slug" is not supported by the reference matcher`)
+	router = wrmatch.New(wrmatch.WithConstraint("slug", func(value string) bool { return true }))
+	router.GET(`/tags/\:all/:name@slug`, "tag")
+	require.EqualError(t, CheckConsistency(router, http.MethodGet, "/tags/a"),
+		`GET "/tags/\\:all/:name@slug": the custom constraint "slug" is not supported by the reference matcher`)
+}
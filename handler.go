@@ -0,0 +1,84 @@
+package wrmatch
+
+import (
+	"context"
+	"net/http"
+)
+
+// paramsKey is the context key of the url params of a request.
+type paramsKey struct{}
+
+// Handler is a http.Handler which dispatches the requests to the handlers
+// matched by a Matcher. Requests which are not matched are delegated to the
+// fallback, e.g. an existing *http.ServeMux, which eases the incremental
+// migration of large services:
+//
+//  router := wrmatch.New()
+//  router.GET("/user/:name", http.HandlerFunc(user))
+//  http.ListenAndServe(":8080", wrmatch.NewHandler(router, legacyMux))
+//
+// The matched values must be a http.Handler or a
+// func(http.ResponseWriter, *http.Request), other values are treated as not
+// matched. The url params are passed in the context of the request, see
+// RequestParams.
+// The requests are matched by their escaped path, see url.URL.EscapedPath,
+// so that an escaped '/' does not separate segments and the param values are
+// escaped, WithUnescapeParams decodes them. Paths of routes with characters
+// which are escaped in a request path, e.g. non-ASCII ones, must be
+// registered escaped.
+type Handler struct {
+	matcher  Matcher
+	fallback http.Handler
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler returns a Handler matching with m. If fallback is nil,
+// unmatched requests are answered with 404 Not Found.
+func NewHandler(m Matcher, fallback http.Handler) *Handler {
+	if fallback == nil {
+		fallback = http.NotFoundHandler()
+	}
+	return &Handler{matcher: m, fallback: fallback}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	value, ps, matched := h.matcher.Match(req.Method, req.URL.EscapedPath())
+	if matched {
+		var handler http.Handler
+		switch v := value.(type) {
+		case http.Handler:
+			handler = v
+		case func(http.ResponseWriter, *http.Request):
+			handler = http.HandlerFunc(v)
+		}
+		if handler != nil {
+			if len(ps) > 0 {
+				req = req.WithContext(NewContext(req.Context(), ps))
+			}
+			handler.ServeHTTP(w, req)
+			return
+		}
+	}
+	h.fallback.ServeHTTP(w, req)
+}
+
+// RequestParams returns the url params of a request dispatched by Handler,
+// nil if there are none.
+func RequestParams(req *http.Request) Params {
+	return ParamsFromContext(req.Context())
+}
+
+// NewContext returns a copy of ctx holding the url params ps, so they can be
+// passed down call chains, see ParamsFromContext.
+func NewContext(ctx context.Context, ps Params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, ps)
+}
+
+// ParamsFromContext returns the url params stored in ctx with NewContext,
+// nil if there are none.
+func ParamsFromContext(ctx context.Context) Params {
+	ps, _ := ctx.Value(paramsKey{}).(Params)
+	return ps
+}
@@ -0,0 +1,97 @@
+package wrmatch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "user "+RequestParams(req).Param("name"))
+	}))
+	router.GET("/func", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "func")
+	})
+	router.GET("/value", "not a handler")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "mux "+req.URL.Path)
+	})
+
+	tests := []struct {
+		handler http.Handler
+		method  string
+		path    string
+		code    int
+		body    string
+	}{
+		{NewHandler(router, mux), http.MethodGet, "/user/gopher", http.StatusOK, "user gopher"},
+		{NewHandler(router, mux), http.MethodGet, "/func", http.StatusOK, "func"},
+		{NewHandler(router, mux), http.MethodGet, "/legacy", http.StatusOK, "mux /legacy"},
+		{NewHandler(router, mux), http.MethodPost, "/user/gopher", http.StatusOK, "mux /user/gopher"},
+		{NewHandler(router, mux), http.MethodGet, "/value", http.StatusOK, "mux /value"},
+		{NewHandler(router, nil), http.MethodGet, "/legacy", http.StatusNotFound, "404 page not found\n"},
+		{NewHandler(Chain(New(), router), nil), http.MethodGet, "/user/chain", http.StatusOK, "user chain"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		require.Equal(t, tt.code, w.Code, tt.path)
+		require.Equal(t, tt.body, w.Body.String(), tt.path)
+	}
+
+	require.Nil(t, RequestParams(httptest.NewRequest(http.MethodGet, "/", nil)))
+}
+
+func TestHandlerEscapedPath(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ps := RequestParams(req)
+		_, _ = io.WriteString(w, ps.Param("name")+" "+ps.Param("filepath"))
+	})
+	tests := []struct {
+		opts []Option
+		path string
+		code int
+		body string
+	}{
+		{nil, "/user/John%20Doe", http.StatusOK, "John%20Doe "},
+		{nil, "/user/a%2Fb", http.StatusOK, "a%2Fb "},
+		{nil, "/user/100%25", http.StatusOK, "100%25 "},
+		{nil, "/user/%2541", http.StatusOK, "%2541 "},
+		{[]Option{WithUnescapeParams()}, "/user/John%20Doe", http.StatusOK, "John Doe "},
+		// an escaped slash does not separate segments
+		{[]Option{WithUnescapeParams()}, "/user/a%2Fb", http.StatusOK, "a/b "},
+		// the values are decoded once
+		{[]Option{WithUnescapeParams()}, "/user/100%25", http.StatusOK, "100% "},
+		{[]Option{WithUnescapeParams()}, "/user/%2541", http.StatusOK, "%41 "},
+		{[]Option{WithUnescapeParams()}, "/src/a%2Fb/c%25d", http.StatusOK, " /a%2Fb/c%d"},
+		{[]Option{WithUnescapeParams()}, "/user/a/b", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		router := New(tt.opts...)
+		router.GET("/user/:name", echo)
+		router.GET("/src/*filepath", echo)
+
+		w := httptest.NewRecorder()
+		NewHandler(router, nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		require.Equal(t, tt.code, w.Code, tt.path)
+		require.Equal(t, tt.body, w.Body.String(), tt.path)
+	}
+}
+
+func TestParamsContext(t *testing.T) {
+	ps := Params{{"name", "gopher"}}
+	ctx := NewContext(context.Background(), ps)
+	require.Equal(t, ps, ParamsFromContext(ctx))
+	require.Nil(t, ParamsFromContext(context.Background()))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	require.Equal(t, ps, RequestParams(req))
+}
@@ -0,0 +1,56 @@
+package wrmatch
+
+// WithOnMatch set a function which is called for every match with the
+// method, the request path and the RouteInfo of the matched route, so
+// observability pipelines can enrich their events with the name and the
+// metadata of the route without a second lookup into the route table.
+// The Methods of the RouteInfo hold the request method only.
+// fn is called synchronously by the matching goroutine.
+// Default: none
+func WithOnMatch(fn func(method, path string, route RouteInfo)) Option {
+	return func(r *Options) {
+		r.onMatch = fn
+	}
+}
+
+// WithOnMiss set a function which is called with the method and the request
+// path of every match no route matches.
+// fn is called synchronously by the matching goroutine.
+// Default: none
+func WithOnMiss(fn func(method, path string)) Option {
+	return func(r *Options) {
+		r.onMiss = fn
+	}
+}
+
+// matchedRoute returns the RouteInfo of the route of the leaf matched for
+// method, value is the value of the match.
+func matchedRoute(method string, leaf *node, value interface{}) RouteInfo {
+	info := RouteInfo{Methods: []string{method}, Value: value}
+	if leaf.meta != nil {
+		info.Path = leaf.meta.route
+		info.Name = leaf.meta.name
+		info.Params = leaf.meta.routeParams()
+		info.CacheHint = leaf.meta.routeCacheHint()
+	}
+	return info
+}
+
+// routeParams returns a copy of the params added to the matches of the
+// route, nil if there are none.
+func (m *routeMeta) routeParams() Params {
+	if len(m.params) == 0 {
+		return nil
+	}
+	return append(Params(nil), m.params...)
+}
+
+// routeCacheHint returns a copy of the cache hint of the route, nil if
+// there is none.
+func (m *routeMeta) routeCacheHint() *CacheHint {
+	if m.cacheHint == nil {
+		return nil
+	}
+	hint := *m.cacheHint
+	return &hint
+}
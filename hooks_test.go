@@ -0,0 +1,46 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterHooks(t *testing.T) {
+	var matches []RouteInfo
+	var misses []string
+	router := New(
+		WithOnMatch(func(method, path string, route RouteInfo) {
+			matches = append(matches, route)
+		}),
+		WithOnMiss(func(method, path string) {
+			misses = append(misses, method+" "+path)
+		}),
+	)
+	router.GET("/posts/:page=1", "posts").Name("posts")
+	router.SetCacheHint(http.MethodGet, "/posts/:page", CacheHint{Cacheable: true, TTL: time.Minute})
+
+	_, _, matched := router.Match(http.MethodGet, "/posts")
+	require.True(t, matched)
+	require.Equal(t, []RouteInfo{
+		{Methods: []string{http.MethodGet}, Path: "/posts", Value: "posts", Params: Params{{"page", "1"}}},
+	}, matches)
+
+	res := router.MatchResult(http.MethodGet, "/posts/2")
+	require.True(t, res.Matched)
+	require.Len(t, matches, 2)
+	require.Equal(t, RouteInfo{
+		Methods:   []string{http.MethodGet},
+		Path:      "/posts/:page",
+		Value:     "posts",
+		Name:      "posts",
+		CacheHint: &CacheHint{Cacheable: true, TTL: time.Minute},
+	}, matches[1])
+
+	_, _, matched = router.Match(http.MethodPost, "/posts")
+	require.False(t, matched)
+	require.Equal(t, []string{"POST /posts"}, misses)
+	require.Len(t, matches, 2)
+}
@@ -0,0 +1,91 @@
+package wrmatch
+
+import (
+	"sort"
+	"sync"
+)
+
+// KeyedMatcher matches paths like Pattern, but holds a trie per key, e.g. a
+// tenant, topic or verb, for users which have no HTTP methods to key the
+// tries of a Router by.
+type KeyedMatcher struct {
+	// mu guards patterns, if WithConcurrentWrites is enabled
+	mu       sync.RWMutex
+	patterns map[string]*Pattern
+	opts     []Option
+	Options
+}
+
+// NewKeyed returns a new initialized KeyedMatcher, the options apply to the
+// Pattern of every key.
+// Path auto-correction, including trailing slashes, is enabled by default.
+func NewKeyed(opts ...Option) *KeyedMatcher {
+	return &KeyedMatcher{
+		patterns: make(map[string]*Pattern),
+		opts:     opts,
+		Options:  NewPattern(opts...).Options,
+	}
+}
+
+// pattern returns the Pattern of key, which is created if create is true.
+func (k *KeyedMatcher) pattern(key string, create bool) *Pattern {
+	if k.concurrentWrites {
+		if create {
+			k.mu.Lock()
+			defer k.mu.Unlock()
+		} else {
+			k.mu.RLock()
+			defer k.mu.RUnlock()
+		}
+	}
+	p := k.patterns[key]
+	if p == nil && create {
+		p = NewPattern(k.opts...)
+		k.patterns[key] = p
+	}
+	return p
+}
+
+// Add registers a new value with the given key and path.
+func (k *KeyedMatcher) Add(key, path string, value interface{}) *KeyedMatcher {
+	k.pattern(key, true).Add(path, value)
+	return k
+}
+
+// Remove removes the value registered with the given key and path, which
+// must be the registered pattern, e.g. "/user/:name", not a request path.
+// It reports whether a value was registered for the path.
+func (k *KeyedMatcher) Remove(key, path string) bool {
+	if p := k.pattern(key, false); p != nil {
+		return p.Remove(path)
+	}
+	return false
+}
+
+// Match returns the value registered with key matching the given path, and
+// the path parameters of the match.
+func (k *KeyedMatcher) Match(key, path string) (interface{}, Params, bool) {
+	if p := k.pattern(key, false); p != nil {
+		return p.Match(path)
+	}
+	return k.notFoundValue, nil, false
+}
+
+// Pattern returns the Pattern of key, or nil if nothing was added for it.
+func (k *KeyedMatcher) Pattern(key string) *Pattern {
+	return k.pattern(key, false)
+}
+
+// Keys returns the keys values were added for in lexical order.
+func (k *KeyedMatcher) Keys() []string {
+	if k.concurrentWrites {
+		k.mu.RLock()
+		defer k.mu.RUnlock()
+	}
+	keys := make([]string, 0, len(k.patterns))
+	for key := range k.patterns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package wrmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedMatcher(t *testing.T) {
+	keyed := NewKeyed()
+	keyed.Add("orders", "/eu/:id", "orders-eu").
+		Add("orders", "/us/*rest", "orders-us").
+		Add("payments", "/eu/:id", "payments-eu")
+
+	v, ps, matched := keyed.Match("orders", "/eu/42")
+	require.True(t, matched)
+	require.Equal(t, "orders-eu", v)
+	require.Equal(t, "42", ps.Param("id"))
+
+	v, ps, matched = keyed.Match("orders", "/us/a/b")
+	require.True(t, matched)
+	require.Equal(t, "orders-us", v)
+	require.Equal(t, "/a/b", ps.Param("rest"))
+
+	v, _, matched = keyed.Match("payments", "/eu/42")
+	require.True(t, matched)
+	require.Equal(t, "payments-eu", v)
+
+	_, _, matched = keyed.Match("payments", "/us/42")
+	require.False(t, matched)
+	_, _, matched = keyed.Match("refunds", "/eu/42")
+	require.False(t, matched)
+
+	require.Equal(t, []string{"orders", "payments"}, keyed.Keys())
+	require.NotNil(t, keyed.Pattern("orders"))
+	require.Nil(t, keyed.Pattern("refunds"))
+
+	require.True(t, keyed.Remove("payments", "/eu/:id"))
+	require.False(t, keyed.Remove("payments", "/eu/:id"))
+	require.False(t, keyed.Remove("refunds", "/eu/:id"))
+	_, _, matched = keyed.Match("payments", "/eu/42")
+	require.False(t, matched)
+}
+
+func TestKeyedMatcherOptions(t *testing.T) {
+	keyed := NewKeyed(WithNotFoundValue("none"), WithConcurrentWrites())
+	keyed.Add("a", "/x/", "x")
+
+	v, _, matched := keyed.Match("a", "/x")
+	require.True(t, matched)
+	require.Equal(t, "x", v)
+
+	v, _, matched = keyed.Match("b", "/x/")
+	require.False(t, matched)
+	require.Equal(t, "none", v)
+}
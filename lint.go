@@ -0,0 +1,117 @@
+package wrmatch
+
+import (
+	"strconv"
+	"strings"
+)
+
+// LintIssue is a violation of a LintRule by a registered route.
+type LintIssue struct {
+	// Rule is the name of the violated rule.
+	Rule   string
+	Method string
+	Path   string
+	// Message describes the violation.
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return i.Rule + ": " + i.Method + " " + i.Path + ": " + i.Message
+}
+
+// LintRule checks a single route of the route table.
+// Check returns a non-empty message if the route violates the rule.
+type LintRule struct {
+	Name  string
+	Check func(route Route) string
+}
+
+// Lint checks every registered route of r against the given rules and
+// returns the violations in the stable order of Router.Routes.
+// It is meant to enforce routing standards in CI, e.g.
+//
+//  issues := wrmatch.Lint(router, wrmatch.LintNoRootCatchAll(), wrmatch.LintMaxDepth(6))
+func Lint(r *Router, rules ...LintRule) []LintIssue {
+	var issues []LintIssue
+	for _, route := range r.Routes() {
+		for _, rule := range rules {
+			if msg := rule.Check(route); msg != "" {
+				issues = append(issues, LintIssue{
+					Rule:    rule.Name,
+					Method:  route.Method,
+					Path:    route.Path,
+					Message: msg,
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// LintNoRootCatchAll reports catch-all routes directly below the root,
+// e.g. "/*path", which shadow every unregistered path.
+func LintNoRootCatchAll() LintRule {
+	return LintRule{
+		Name: "no-root-catch-all",
+		Check: func(route Route) string {
+			if strings.HasPrefix(route.Path, "/*") {
+				return "catch-all at the root matches every path"
+			}
+			return ""
+		},
+	}
+}
+
+// LintMaxDepth reports routes with more than max path segments.
+func LintMaxDepth(max int) LintRule {
+	return LintRule{
+		Name: "max-depth",
+		Check: func(route Route) string {
+			if depth := strings.Count(strings.Trim(route.Path, "/"), "/") + 1; depth > max {
+				return "depth " + strconv.Itoa(depth) + " exceeds " + strconv.Itoa(max)
+			}
+			return ""
+		},
+	}
+}
+
+// LintMaxParams reports routes with more than max wildcards.
+func LintMaxParams(max int) LintRule {
+	return LintRule{
+		Name: "max-params",
+		Check: func(route Route) string {
+			if n := int(countParams(route.Path)); n > max {
+				return strconv.Itoa(n) + " wildcards exceed " + strconv.Itoa(max)
+			}
+			return ""
+		},
+	}
+}
+
+// LintParamConstraints reports routes with a named parameter without a
+// constraint, e.g. "/user/:id" instead of "/user/:id<int>".
+func LintParamConstraints() LintRule {
+	return LintRule{
+		Name: "param-constraints",
+		Check: func(route Route) string {
+			if name := unconstrainedParam(route.Path); name != "" {
+				return "parameter '" + name + "' has no constraint"
+			}
+			return ""
+		},
+	}
+}
+
+// LintNamedRoutes reports routes without a name, see Router.Name, so every
+// route can be referenced by URLFor.
+func LintNamedRoutes() LintRule {
+	return LintRule{
+		Name: "named-routes",
+		Check: func(route Route) string {
+			if route.Name == "" {
+				return "route has no name"
+			}
+			return ""
+		},
+	}
+}
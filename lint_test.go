@@ -0,0 +1,57 @@
+package wrmatch
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	router := New()
+	router.Add(http.MethodDelete, "/*all", "default")
+	router.GET("/a/b/c/d", "deep")
+	router.POST("/user/:name/:id", "user")
+	router.GET("/user/:name", "user")
+	// an escaped ':' is no wildcard
+	router.GET("/time\\:now/:zone", "time")
+
+	noUpperCase := LintRule{
+		Name: "lower-case",
+		Check: func(route Route) string {
+			if route.Path != strings.ToLower(route.Path) {
+				return "path must be lower case"
+			}
+			return ""
+		},
+	}
+	router.GET("/Upper", "upper")
+
+	issues := Lint(router, LintNoRootCatchAll(), LintMaxDepth(3), LintMaxParams(1), noUpperCase)
+	require.Equal(t, []LintIssue{
+		{"no-root-catch-all", http.MethodDelete, "/*all", "catch-all at the root matches every path"},
+		{"lower-case", http.MethodGet, "/Upper", "path must be lower case"},
+		{"max-depth", http.MethodGet, "/a/b/c/d", "depth 4 exceeds 3"},
+		{"max-params", http.MethodPost, "/user/:name/:id", "2 wildcards exceed 1"},
+	}, issues)
+	require.Equal(t, "max-depth: GET /a/b/c/d: depth 4 exceeds 3", issues[2].String())
+
+	require.Empty(t, Lint(New(), LintNoRootCatchAll()))
+}
+
+func TestLintConstraintsAndNames(t *testing.T) {
+	router := New()
+	router.GET("/user/:id<int>", "user").Name("user")
+	router.GET("/post/:id<int>/:slug.html", "post").Name("post")
+	router.GET("/files/*path", "files")
+	router.GET("/orgs/:org<alpha>/*/settings", "settings").Name("settings")
+
+	require.Equal(t, []LintIssue{
+		{"param-constraints", http.MethodGet, "/orgs/:org<alpha>/:$1/settings", "parameter '$1' has no constraint"},
+		{"param-constraints", http.MethodGet, "/post/:id<int>/:slug.html", "parameter 'slug' has no constraint"},
+	}, Lint(router, LintParamConstraints()))
+	require.Equal(t, []LintIssue{
+		{"named-routes", http.MethodGet, "/files/*path", "route has no name"},
+	}, Lint(router, LintNamedRoutes()))
+}
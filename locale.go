@@ -0,0 +1,39 @@
+package wrmatch
+
+import "strings"
+
+// LocaleParam is the Param name under which the locale prefix of the request
+// path is stored, if WithLocalePrefix is set.
+var LocaleParam = "$locale"
+
+// WithLocalePrefix recognizes an optional leading locale segment of the
+// request path, e.g. "/en/..." or "/de-DE/...". The segment is matched case
+// insensitively, stripped into the LocaleParam param in the form given in
+// locales, and the remainder of the path is matched. If the remainder does not
+// match, the full path is matched instead.
+// It is used by Router only.
+// Default: none
+func WithLocalePrefix(locales []string) Option {
+	return func(r *Options) {
+		r.locales = make(map[string]string, len(locales))
+		for _, locale := range locales {
+			r.locales[strings.ToLower(locale)] = locale
+		}
+	}
+}
+
+// splitLocale splits the locale prefix from path. It reports false if path
+// does not start with a known locale.
+func (o *Options) splitLocale(path string) (locale, rest string, ok bool) {
+	if len(o.locales) == 0 || len(path) < 2 || path[0] != '/' {
+		return "", "", false
+	}
+	seg, rest := path[1:], "/"
+	if i := strings.IndexByte(seg, '/'); i >= 0 {
+		seg, rest = seg[:i], seg[i:]
+	}
+	if locale, ok = o.locales[seg]; !ok {
+		locale, ok = o.locales[strings.ToLower(seg)]
+	}
+	return locale, rest, ok
+}
@@ -0,0 +1,44 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterLocalePrefix(t *testing.T) {
+	router := New(WithLocalePrefix([]string{"en", "de-DE"}))
+	router.GET("/", "index")
+	router.GET("/user/:name", "user")
+	router.GET("/en/legacy", "legacy")
+
+	tests := []struct {
+		path   string
+		value  interface{}
+		params Params
+	}{
+		{"/user/gopher", "user", Params{Param{"name", "gopher"}}},
+		{"/en/user/gopher", "user", Params{Param{"name", "gopher"}, Param{LocaleParam, "en"}}},
+		{"/de-de/user/gopher", "user", Params{Param{"name", "gopher"}, Param{LocaleParam, "de-DE"}}},
+		{"/en", "index", Params{Param{LocaleParam, "en"}}},
+		{"/en/", "index", Params{Param{LocaleParam, "en"}}},
+		{"/en/legacy", "legacy", nil},
+	}
+	for _, tt := range tests {
+		v, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.True(t, matched, tt.path)
+		require.Equal(t, tt.value, v, tt.path)
+		require.Equal(t, tt.params, ps, tt.path)
+
+		_, view, _ := router.MatchView(http.MethodGet, tt.path)
+		require.Equal(t, tt.params, view.Params(), tt.path)
+	}
+
+	_, _, matched := router.Match(http.MethodGet, "/fr/user/gopher")
+	require.False(t, matched)
+
+	res := router.MatchRedirect(http.MethodGet, "/en/USER/gopher")
+	require.False(t, res.Matched)
+	require.Equal(t, "/en/user/gopher", res.Redirect)
+}
@@ -0,0 +1,134 @@
+package wrmatch
+
+// ConflictPolicy decides how Router.Merge handles routes of the merged router
+// which conflict with the routes of the router.
+type ConflictPolicy uint8
+
+const (
+	// ConflictFail fails the merge on the first conflict, the router is
+	// left unchanged.
+	ConflictFail ConflictPolicy = iota
+	// ConflictKeepExisting skips the conflicting incoming routes.
+	ConflictKeepExisting
+	// ConflictReplace removes the conflicting existing routes.
+	ConflictReplace
+)
+
+// Merge adds all routes of other to the router, the routes keep their
+// metadata. A route conflicts, if the same method and path is registered
+// already, or if its wildcards conflict with an existing route, see
+// ConflictError. Conflicts are resolved according to policy.
+// Overlays of other are not merged.
+//
+// Not concurrency-safe!
+func (r *Router) Merge(other *Router, policy ConflictPolicy) error {
+	if r.sealed {
+		return ErrSealed
+	}
+	if policy == ConflictFail {
+		// check on a copy first, so the router is unchanged on conflicts
+		if err := r.Clone().merge(other, policy); err != nil {
+			return err
+		}
+	}
+	return r.merge(other, policy)
+}
+
+func (r *Router) merge(other *Router, policy ConflictPolicy) error {
+	for _, method := range other.methods() {
+		var err error
+		other.trees[method].walk("", func(path string, n *node) bool {
+			err = r.mergeRoute(method, path, n, policy)
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeRoute adds the route of the leaf n of another router.
+func (r *Router) mergeRoute(method, path string, n *node, policy ConflictPolicy) error {
+	for {
+		if policy != ConflictFail && r.findRoute(method, path) != nil {
+			if policy == ConflictKeepExisting {
+				return nil
+			}
+			r.Remove(method, path)
+		}
+
+		err := r.addE(method, func() { r.add(method, path, unwrapValue(n.value)) })
+		if err == nil {
+			r.copyMeta(method, path, n)
+			return nil
+		}
+
+		conflict, ok := err.(*ConflictError)
+		if !ok || policy == ConflictFail {
+			return err
+		}
+		if policy == ConflictKeepExisting {
+			return nil
+		}
+		if !r.Remove(method, conflict.Existing) {
+			return err
+		}
+	}
+}
+
+// Merge adds all patterns of other to the pattern, conflicts are resolved
+// according to policy like for Router.Merge.
+//
+// Not concurrency-safe, unless WithConcurrentWrites is enabled!
+func (r *Pattern) Merge(other *Pattern, policy ConflictPolicy) error {
+	entries := other.List()
+	if r.concurrentWrites {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	if policy == ConflictFail {
+		// check on a copy first, so the pattern is unchanged on conflicts
+		if err := r.clone().merge(entries, policy); err != nil {
+			return err
+		}
+	}
+	return r.merge(entries, policy)
+}
+
+func (r *Pattern) merge(entries []PatternEntry, policy ConflictPolicy) error {
+	for _, e := range entries {
+		if err := r.mergeEntry(e.Path, e.Value, policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeEntry adds the pattern of another Pattern.
+func (r *Pattern) mergeEntry(path string, value interface{}, policy ConflictPolicy) error {
+	for {
+		if policy != ConflictFail && r.root.findRoute(path) != nil {
+			if policy == ConflictKeepExisting {
+				return nil
+			}
+			r.remove(path)
+		}
+
+		err := registrationError(func() { r.add(path, value) })
+		if err == nil {
+			return nil
+		}
+
+		conflict, ok := err.(*ConflictError)
+		if !ok || policy == ConflictFail {
+			return err
+		}
+		if policy == ConflictKeepExisting {
+			return nil
+		}
+		if !r.remove(conflict.Existing) {
+			return err
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newMergeRouters() (*Router, *Router) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/team", "team")
+
+	other := New()
+	other.GET("/user/:id/about", "about")
+	other.GET("/team", "other-team")
+	other.POST("/team", "create-team")
+	other.GET("/blog", "blog")
+	other.Disable(http.MethodGet, "/blog")
+	return router, other
+}
+
+func TestRouterMerge(t *testing.T) {
+	router, other := newMergeRouters()
+	err := router.Merge(other, ConflictFail)
+	require.EqualError(t, err, "a value is already registered for path '/team'")
+	require.Equal(t, []Route{
+		{http.MethodGet, "/team", "team", false, ""},
+		{http.MethodGet, "/user/:name", "user", false, ""},
+	}, router.Routes())
+
+	router, other = newMergeRouters()
+	require.NoError(t, router.Merge(other, ConflictKeepExisting))
+	require.Equal(t, []Route{
+		{http.MethodGet, "/blog", "blog", true, ""},
+		{http.MethodGet, "/team", "team", false, ""},
+		{http.MethodGet, "/user/:name", "user", false, ""},
+		{http.MethodPost, "/team", "create-team", false, ""},
+	}, router.Routes())
+
+	router, other = newMergeRouters()
+	require.NoError(t, router.Merge(other, ConflictReplace))
+	require.Equal(t, []Route{
+		{http.MethodGet, "/blog", "blog", true, ""},
+		{http.MethodGet, "/team", "other-team", false, ""},
+		{http.MethodGet, "/user/:id/about", "about", false, ""},
+		{http.MethodPost, "/team", "create-team", false, ""},
+	}, router.Routes())
+	v, ps, matched := router.Match(http.MethodGet, "/user/gopher/about")
+	require.True(t, matched)
+	require.Equal(t, "about", v)
+	require.Equal(t, Params{Param{"id", "gopher"}}, ps)
+
+	router = New()
+	router.GET("/team", "team")
+	other = New()
+	other.GET("/blog", "blog")
+	require.NoError(t, router.Merge(other, ConflictFail))
+	require.True(t, router.HasRoute(http.MethodGet, "/blog"))
+}
+
+func newMergePatterns() (*Pattern, *Pattern) {
+	pattern := NewPattern()
+	pattern.Add("/user/:name", "user")
+	pattern.Add("/team", "team")
+
+	other := NewPattern()
+	other.Add("/user/:id/about", "about")
+	other.Add("/team", "other-team")
+	other.Add("/blog", "blog")
+	return pattern, other
+}
+
+func TestPatternMerge(t *testing.T) {
+	pattern, other := newMergePatterns()
+	err := pattern.Merge(other, ConflictFail)
+	require.EqualError(t, err, "a value is already registered for path '/team'")
+	require.Equal(t, []PatternEntry{
+		{"/team", "team"},
+		{"/user/:name", "user"},
+	}, pattern.List())
+
+	pattern, other = newMergePatterns()
+	require.NoError(t, pattern.Merge(other, ConflictKeepExisting))
+	require.Equal(t, []PatternEntry{
+		{"/blog", "blog"},
+		{"/team", "team"},
+		{"/user/:name", "user"},
+	}, pattern.List())
+
+	pattern, other = newMergePatterns()
+	require.NoError(t, pattern.Merge(other, ConflictReplace))
+	require.Equal(t, []PatternEntry{
+		{"/blog", "blog"},
+		{"/team", "other-team"},
+		{"/user/:id/about", "about"},
+	}, pattern.List())
+	v, ps, matched := pattern.Match("/user/gopher/about")
+	require.True(t, matched)
+	require.Equal(t, "about", v)
+	require.Equal(t, Params{Param{"id", "gopher"}}, ps)
+
+	pattern = NewPattern()
+	pattern.Add("/team", "team")
+	other = NewPattern()
+	other.Add("/blog/:id", "blog")
+	require.NoError(t, pattern.Merge(other, ConflictFail))
+	_, ps, matched = pattern.Match("/blog/1")
+	require.True(t, matched)
+	require.Equal(t, "1", ps.Param("id"))
+}
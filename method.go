@@ -0,0 +1,36 @@
+package wrmatch
+
+import "strings"
+
+// WithMethodNormalization upper cases the methods passed to the router,
+// at registration and at match time, so Match("get", "/x") matches a route
+// added with GET.
+// Default: disable
+func WithMethodNormalization() Option {
+	return func(r *Options) {
+		r.normalizeMethods = true
+	}
+}
+
+// WithMethodAliases maps the methods passed to the router to other methods,
+// at registration and at match time, e.g. {"FETCH": "GET"}.
+// If the method normalization is enabled, the method is upper cased before
+// it is looked up in aliases, so the keys should be upper cased as well.
+// Default: none
+func WithMethodAliases(aliases map[string]string) Option {
+	return func(r *Options) {
+		r.methodAliases = aliases
+	}
+}
+
+// normalizeMethod returns the method under which the routes of method are
+// stored, according to the method normalization and aliases.
+func (o *Options) normalizeMethod(method string) string {
+	if o.normalizeMethods {
+		method = strings.ToUpper(method)
+	}
+	if alias, ok := o.methodAliases[method]; ok {
+		return alias
+	}
+	return method
+}
@@ -0,0 +1,86 @@
+package wrmatch
+
+import (
+	"hash/fnv"
+	"math"
+	"strconv"
+)
+
+// mirrorRule is the value stored for a pattern of a MirrorSelector.
+type mirrorRule struct {
+	target   string
+	rate     float64
+	keyParam string
+}
+
+// MirrorSelector selects the requests whose traffic is shadowed to another
+// target, e.g. a canary deployment. The rules are patterns like the ones of
+// Pattern, each with a target and a sample rate.
+type MirrorSelector struct {
+	pattern *Pattern
+}
+
+// NewMirrorSelector returns a new MirrorSelector, the options apply to the
+// underlying Pattern.
+func NewMirrorSelector(opts ...Option) *MirrorSelector {
+	return &MirrorSelector{pattern: NewPattern(opts...)}
+}
+
+// Add registers a rule mirroring the requests matching path to target.
+// rate is the share of the requests in [0, 1] which are mirrored. The
+// sampling is deterministic: the value of the url param keyParam, or the
+// request path if keyParam is empty, is hashed, so all requests with the same
+// key are either mirrored or not, e.g. Add("/user/:id", "shadow", 0.1, "id")
+// mirrors all requests of 10% of the users.
+func (s *MirrorSelector) Add(path, target string, rate float64, keyParam string) *MirrorSelector {
+	if target == "" {
+		panic("target must not be empty")
+	}
+	if !(rate >= 0 && rate <= 1) {
+		panic("rate must be in [0, 1], got " + strconv.FormatFloat(rate, 'g', -1, 64))
+	}
+	s.pattern.Add(path, &mirrorRule{target, rate, keyParam})
+	return s
+}
+
+// ShouldMirror returns the target the request for path is mirrored to,
+// ok is false if no rule matches the path or the request is not sampled.
+// Like Pattern.Match, it may run concurrently with Add if WithConcurrentWrites
+// is enabled.
+func (s *MirrorSelector) ShouldMirror(path string) (target string, ok bool) {
+	if s.pattern.concurrentWrites {
+		s.pattern.mu.RLock()
+		defer s.pattern.mu.RUnlock()
+	}
+	leaf, ps := s.pattern.match(path, s.pattern.paramsNew)
+	if leaf == nil {
+		return "", false
+	}
+	rule := unwrapValue(leaf.value).(*mirrorRule)
+	key := path
+	if rule.keyParam != "" {
+		key = ps.Param(rule.keyParam)
+	}
+	if !sampled(key, rule.rate) {
+		return "", false
+	}
+	return rule.target, true
+}
+
+// sampled reports whether key falls into the share rate of all keys.
+func sampled(key string, rate float64) bool {
+	if rate >= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	// FNV-1a spreads short keys poorly over the high bits, so they are mixed
+	// with the finalizer of MurmurHash3
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return float64(x) < rate*math.MaxUint64
+}
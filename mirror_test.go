@@ -0,0 +1,69 @@
+package wrmatch
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorSelector(t *testing.T) {
+	s := NewMirrorSelector()
+	s.Add("/user/:id", "shadow", 0.5, "id")
+	s.Add("/health", "never", 0, "")
+	s.Add("/static/*filepath", "always", 1, "")
+
+	_, ok := s.ShouldMirror("/unknown")
+	require.False(t, ok)
+	_, ok = s.ShouldMirror("/health")
+	require.False(t, ok)
+	target, ok := s.ShouldMirror("/static/app.js")
+	require.True(t, ok)
+	require.Equal(t, "always", target)
+
+	// the sampling depends on the key param only and is deterministic
+	mirrored := 0
+	for i := 0; i < 1000; i++ {
+		id := strconv.Itoa(i)
+		target, ok := s.ShouldMirror("/user/" + id)
+		if ok {
+			require.Equal(t, "shadow", target)
+			mirrored++
+		}
+		_, again := s.ShouldMirror("/user/" + id + "/")
+		require.Equal(t, ok, again, id)
+	}
+	require.InDelta(t, 500, mirrored, 100)
+
+	require.Panics(t, func() { s.Add("/a", "", 1, "") })
+	require.Panics(t, func() { s.Add("/b", "t", 1.5, "") })
+	require.Panics(t, func() { s.Add("/c", "t", -1, "") })
+}
+
+func TestMirrorSelectorConcurrentWrites(t *testing.T) {
+	s := NewMirrorSelector(WithConcurrentWrites())
+	s.Add("/static/*filepath", "always", 1, "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Add("/p"+strconv.Itoa(i)+"/"+strconv.Itoa(j), "shadow", 1, "")
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				target, ok := s.ShouldMirror("/static/app.js")
+				require.True(t, ok)
+				require.Equal(t, "always", target)
+			}
+		}()
+	}
+	wg.Wait()
+	_, ok := s.ShouldMirror("/p3/99")
+	require.True(t, ok)
+}
@@ -0,0 +1,136 @@
+package wrmatch
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// routeName is the method and path of a named route.
+type routeName struct {
+	method, path string
+}
+
+// Name names the route added last, e.g.
+//
+//  router.GET("/users/:id", v).Name("user.show")
+//
+// Names must be unique, the named route can be used with URLFor.
+func (r *Router) Name(name string) *Router {
+	r.checkSealed()
+	if r.last == nil {
+		panic("no route to name '" + name + "'")
+	}
+	r.setName(r.last.method, r.last.path, name)
+	return r
+}
+
+// setName names the route registered with the given method and path.
+func (r *Router) setName(method, path, name string) {
+	r.checkSealed()
+	if name == "" {
+		panic("route name must not be empty in path '" + path + "'")
+	}
+	if rn, ok := r.names[name]; ok && (rn.method != method || rn.path != path) && r.namedRoute(name) != nil {
+		panic("route name '" + name + "' is already registered for path '" + rn.path + "'")
+	}
+	if r.names == nil {
+		r.names = make(map[string]routeName)
+	}
+	r.findRoute(method, path).routeMeta().name = name
+	r.names[name] = routeName{method, path}
+}
+
+// namedRoute returns the node of the route with the given name,
+// nil if there is none.
+func (r *Router) namedRoute(name string) *node {
+	rn, ok := r.names[name]
+	if !ok {
+		return nil
+	}
+	n := r.findRoute(rn.method, rn.path)
+	if n == nil || n.meta == nil || n.meta.name != name {
+		// the route was removed
+		return nil
+	}
+	return n
+}
+
+// URLFor builds the path of the route with the given name by expanding its
+// wildcards with the given params, e.g. URLFor("user.show", map[string]string{"id": "42"})
+// returns "/users/42" for the route "/users/:id". The param values are
+// escaped, catch-all values may contain slashes.
+func (r *Router) URLFor(name string, params map[string]string) (string, error) {
+	n := r.namedRoute(name)
+	if n == nil {
+		return "", errors.New("no route with name '" + name + "'")
+	}
+	return expandPath(n.meta.route, params, &r.Options)
+}
+
+// BuildPath builds a path from the route pattern by expanding its wildcards
+// with the params like URLFor, e.g. BuildPath("/user/:name/files/*path", ps)
+// returns "/user/gopher/files/a/b" for the params name=gopher and
+// path=/a/b. Like Param, only the first param of a key is used, params
+// without a wildcard are ignored.
+// It returns an error if the pattern is not a valid route path, or if a
+// wildcard has no param.
+func BuildPath(pattern string, ps Params) (string, error) {
+	if len(pattern) < 1 || pattern[0] != '/' {
+		return "", errors.New("path must begin with '/' in path '" + pattern + "'")
+	}
+	if err := registrationError(func() { new(node).addRoute(pattern, pattern) }); err != nil {
+		return "", err
+	}
+	params := make(map[string]string, len(ps))
+	for _, p := range ps {
+		if _, ok := params[p.Key]; !ok {
+			params[p.Key] = p.Value
+		}
+	}
+	return expandPath(pattern, params, nil)
+}
+
+// expandPath expands the wildcards of the registered path with params. The
+// values are checked against the constraints known to o, which may be nil.
+func expandPath(path string, params map[string]string, o *Options) (string, error) {
+	var sb strings.Builder
+	for {
+		wildcard, i, _ := findWildcard(path)
+		if i < 0 {
+			sb.WriteString(unescapePath(path))
+			return sb.String(), nil
+		}
+		sb.WriteString(unescapePath(path[:i]))
+		path = path[i+len(wildcard):]
+
+		name, suffix := wildcard[1:], ""
+		if wildcard[0] == ':' {
+			name, suffix = splitSuffix(name)
+		}
+		name, constraint := splitConstraint(name)
+		value, ok := params[name]
+		if !ok {
+			return "", errors.New("missing param '" + name + "'")
+		}
+		if wildcard[0] == ':' {
+			if value == "" {
+				return "", errors.New("empty param '" + name + "'")
+			}
+			if constraint != "" && o.hasConstraint(constraint) && !o.satisfies(constraint, value) {
+				return "", errors.New("param '" + name + "' is not a valid " + constraint)
+			}
+			sb.WriteString(url.PathEscape(value))
+			sb.WriteString(suffix)
+			continue
+		}
+		// catch-all, the '/' in front of it is written already
+		segments := strings.Split(strings.TrimPrefix(value, "/"), "/")
+		for j, segment := range segments {
+			if j > 0 {
+				sb.WriteByte('/')
+			}
+			sb.WriteString(url.PathEscape(segment))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterURLFor(t *testing.T) {
+	router := New()
+	router.GET("/users/:id", "user").Name("user.show")
+	router.GET("/users/:id/files/*filepath", "files").Name("user.files")
+	router.GET("/about", "about").Name("about")
+	router.POST("/users", "create")
+
+	tests := []struct {
+		name   string
+		params map[string]string
+		url    string
+		err    string
+	}{
+		{"user.show", map[string]string{"id": "42"}, "/users/42", ""},
+		{"user.show", map[string]string{"id": "a b/c"}, "/users/a%20b%2Fc", ""},
+		{"user.files", map[string]string{"id": "42", "filepath": "/docs/a b.txt"}, "/users/42/files/docs/a%20b.txt", ""},
+		{"user.files", map[string]string{"id": "42", "filepath": ""}, "/users/42/files/", ""},
+		{"about", nil, "/about", ""},
+		{"user.show", nil, "", "missing param 'id'"},
+		{"user.show", map[string]string{"id": ""}, "", "empty param 'id'"},
+		{"unknown", nil, "", "no route with name 'unknown'"},
+	}
+	for _, tt := range tests {
+		url, err := router.URLFor(tt.name, tt.params)
+		if tt.err != "" {
+			require.EqualError(t, err, tt.err, tt.name)
+			continue
+		}
+		require.NoError(t, err, tt.name)
+		require.Equal(t, tt.url, url, tt.name)
+	}
+
+	require.Equal(t, "user.show", router.Routes()[1].Name)
+	require.Equal(t, "user.files", router.MatchResult(http.MethodGet, "/users/1/files/a").Name)
+	require.Panics(t, func() { router.GET("/team", "team").Name("about") })
+	require.Panics(t, func() { New().Name("empty") })
+
+	// names follow the routes
+	router.Remove(http.MethodGet, "/about")
+	_, err := router.URLFor("about", nil)
+	require.Error(t, err)
+	router.GET("/about-us", "about").Name("about")
+	url, err := router.URLFor("about", nil)
+	require.NoError(t, err)
+	require.Equal(t, "/about-us", url)
+
+	sub := New()
+	sub.GET("/items/:id", "item").Name("item")
+	router.Mount("/shop", sub)
+	url, err = router.URLFor("item", map[string]string{"id": "1"})
+	require.NoError(t, err)
+	require.Equal(t, "/shop/items/1", url)
+
+	clone := router.Clone()
+	url, err = clone.URLFor("user.show", map[string]string{"id": "42"})
+	require.NoError(t, err)
+	require.Equal(t, "/users/42", url)
+}
+
+func TestBuildPath(t *testing.T) {
+	path, err := BuildPath("/user/:name/files/*path", Params{
+		{"name", "gopher"}, {"path", "/a/b c"}, {"name", "other"}, {"unused", "x"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/user/gopher/files/a/b%20c", path)
+
+	pattern := NewPattern()
+	pattern.Add("/src/:owner/:repo", "repo")
+	_, ps, matched := pattern.Match("/src/wyy-go/wrmatch")
+	require.True(t, matched)
+	path, err = BuildPath("/dst/:repo/:owner", ps)
+	require.NoError(t, err)
+	require.Equal(t, "/dst/wrmatch/wyy-go", path)
+
+	_, err = BuildPath("/user/:name/:tab", Params{{"name", "gopher"}})
+	require.EqualError(t, err, "missing param 'tab'")
+	_, err = BuildPath("user/:name", nil)
+	require.Error(t, err)
+	_, err = BuildPath("/user/:name:id", Params{{"name", "gopher"}})
+	require.Error(t, err)
+}
@@ -1,5 +1,11 @@
 package wrmatch
 
+import (
+	"sync"
+	"time"
+	"unsafe"
+)
+
 // Options Router and Pattern option
 type Options struct {
 	// If enabled, get the matched route path. that were
@@ -14,15 +20,149 @@ type Options struct {
 	redirectTrailingSlash bool
 
 	// If enabled, the router tries to fix the current request path, if no
-	// value is registered for it.
-	// First superfluous path elements like ../ or // are removed.
-	// Afterwards the router does a case-insensitive lookup of the cleaned path.
-	// If a value can be found for this route, the router makes a redirection
-	// to the corrected path with status code 301 for GET requests and 308 for
-	// all other request methods.
-	// For example /FOO and /..//Foo could be redirected to /foo.
+	// value is registered for it, by removing superfluous path elements
+	// like ../ or //.
+	// For example /..//foo could be redirected to /foo.
+	// redirectTrailingSlash is independent of this option.
+	redirectCleanPath bool
+
+	// If enabled, the router does a case-insensitive lookup of the current
+	// request path, if no value is registered for it.
+	// For example /FOO could be redirected to /foo.
+	// Only the static segments are folded, the values of the wildcards keep
+	// the casing of the request path, e.g. /USER/AbC matches /user/:id with
+	// the id AbC.
 	// redirectTrailingSlash is independent of this option.
-	redirectFixedPath bool
+	redirectCaseInsensitive bool
+
+	// index the static first path segments, see WithFirstSegmentIndex.
+	firstSegmentIndex bool
+
+	// valueResolver set with WithValueResolver.
+	valueResolver func(stored interface{}, res *MatchResult) interface{}
+
+	// locales maps the lower cased locales of WithLocalePrefix to their form.
+	locales map[string]string
+
+	// prefixes holds the options overridden with Router.ConfigurePrefix.
+	prefixes []prefixOptions
+
+	// upper case the methods, see WithMethodNormalization.
+	normalizeMethods bool
+
+	// methodAliases set with WithMethodAliases.
+	methodAliases map[string]string
+
+	// anyMethods set with WithAnyMethods.
+	anyMethods []string
+
+	// slowMatch and its threshold set with WithSlowMatch.
+	slowMatchThreshold time.Duration
+	slowMatch          func(trace MatchTrace)
+
+	// onMatch and onMiss set with WithOnMatch and WithOnMiss.
+	onMatch func(method, path string, route RouteInfo)
+	onMiss  func(method, path string)
+
+	// policy set with WithPolicy.
+	policy *TablePolicy
+
+	// autoOptions and optionsValue set with WithAutoOptions.
+	autoOptions  bool
+	optionsValue func(allowed []string) interface{}
+
+	// guard a Pattern with a lock, see WithConcurrentWrites.
+	concurrentWrites bool
+
+	// percent-decode the param values, see WithUnescapeParams.
+	unescapeParams bool
+
+	// match empty last segments as params, see WithAllowEmptyParams.
+	allowEmptyParams bool
+
+	// strip the leading '/' of catch-all values, see WithTrimCatchAllSlash.
+	trimCatchAllSlash bool
+
+	// the Param name of the matched route path, see WithMatchedRoutePathKey.
+	matchedRoutePathKey string
+
+	// constraints registered with WithConstraint.
+	constraints map[string]func(value string) bool
+
+	// accept the wildcards of net/http, see WithServeMuxPatterns.
+	serveMuxPatterns bool
+
+	// memoryBudget set with WithMemoryBudget.
+	memoryBudget int64
+
+	// appendValues set with WithAppendValues.
+	appendValues bool
+
+	// notFoundValue set with WithNotFoundValue.
+	notFoundValue interface{}
+
+	// codec used to (de)serialize the values of the route table.
+	valueCodec ValueCodec
+
+	// the distinct values tracked per wildcard, see WithWildcardStats.
+	wildcardStats int
+}
+
+// ValueCodec returns the codec used to (de)serialize values.
+// Default: StringCodec
+func (o *Options) ValueCodec() ValueCodec {
+	if o.valueCodec == nil {
+		return StringCodec{}
+	}
+	return o.valueCodec
+}
+
+// fixPath returns the corrected path for a path which could not be matched
+// in root, according to the enabled path corrections.
+func (o *Options) fixPath(root *node, path string) (string, bool) {
+	if o.redirectCleanPath && o.redirectCaseInsensitive {
+		// the cleaned path is only an intermediate of the case-insensitive
+		// lookup, which copies it, so it is cleaned into a pooled buffer
+		bp := pathBufPool.Get().(*[]byte)
+		buf := CleanPathBuf((*bp)[:0], path)
+		fixedPath, found := root.findCaseInsensitivePathFor(bytesToString(buf), o.redirectTrailingSlash, o)
+		*bp = buf
+		pathBufPool.Put(bp)
+		return fixedPath, found
+	}
+	if o.redirectCaseInsensitive {
+		return root.findCaseInsensitivePathFor(path, o.redirectTrailingSlash, o)
+	}
+	if !o.redirectCleanPath {
+		return path, false
+	}
+	// the corrected path is not corrected again, so the cleaned path gets
+	// its trailing slash fixed here
+	path = CleanPath(path)
+	if o.redirectTrailingSlash && path != "/" {
+		if leaf, _, tsr := root.lookupFrom(path, 0, nil, nil, o); leaf == nil && tsr {
+			if path[len(path)-1] == '/' {
+				path = path[:len(path)-1]
+			} else {
+				path += "/"
+			}
+		}
+	}
+	return path, true
+}
+
+// pathBufPool holds the buffers of the intermediate corrected paths.
+var pathBufPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 128)
+		return &buf
+	},
+}
+
+// bytesToString returns b as a string without copying, b must not be
+// modified while the string is in use.
+func bytesToString(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
 }
 
 // Option for Router, Pattern
@@ -37,12 +177,61 @@ func WithDisableRedirectTrailingSlash() Option {
 	}
 }
 
+// WithRedirectTrailingSlash enable automatic redirection if the current route can't be matched but a
+// value for the path with (without) the trailing slash exists
+// Default: enabled
+func WithRedirectTrailingSlash() Option {
+	return func(r *Options) {
+		r.redirectTrailingSlash = true
+	}
+}
+
 // WithDisableRedirectFixedPath diable the router tries to fix the current request path, if no
 // value is registered for it.
+// It disables both the path cleaning and the case-insensitive lookup.
 // Default: enabled
 func WithDisableRedirectFixedPath() Option {
 	return func(r *Options) {
-		r.redirectFixedPath = false
+		r.redirectCleanPath = false
+		r.redirectCaseInsensitive = false
+	}
+}
+
+// WithRedirectCleanPath enable the removal of superfluous path elements
+// like ../ or // if no value is registered for the current request path.
+// Default: enabled
+func WithRedirectCleanPath() Option {
+	return func(r *Options) {
+		r.redirectCleanPath = true
+	}
+}
+
+// WithDisableRedirectCleanPath disable the removal of superfluous path elements
+// like ../ or // if no value is registered for the current request path.
+// Default: enabled
+func WithDisableRedirectCleanPath() Option {
+	return func(r *Options) {
+		r.redirectCleanPath = false
+	}
+}
+
+// WithRedirectCaseInsensitive enable the case-insensitive lookup
+// if no value is registered for the current request path.
+// Only the static segments are folded, the url params keep the casing of
+// the request path, so case-sensitive IDs are captured unchanged.
+// Default: enabled
+func WithRedirectCaseInsensitive() Option {
+	return func(r *Options) {
+		r.redirectCaseInsensitive = true
+	}
+}
+
+// WithDisableRedirectCaseInsensitive disable the case-insensitive lookup
+// if no value is registered for the current request path.
+// Default: enabled
+func WithDisableRedirectCaseInsensitive() Option {
+	return func(r *Options) {
+		r.redirectCaseInsensitive = false
 	}
 }
 
@@ -55,3 +244,106 @@ func WithSaveMatchedRoutePath() Option {
 		r.saveMatchedRoutePath = true
 	}
 }
+
+// WithValueResolver set a function which is called on every successful match
+// with the stored value and the result of the match; its return value is
+// returned as the matched value instead. This allows to store indirections,
+// e.g. route IDs, and resolve them to live values from a side table at match
+// time. Overlays of a Router use their own resolver.
+// Default: none
+func WithValueResolver(fn func(stored interface{}, res *MatchResult) interface{}) Option {
+	return func(r *Options) {
+		r.valueResolver = fn
+	}
+}
+
+// resolveValue calls the value resolver for the stored value of the leaf.
+func (o *Options) resolveValue(leaf *node, value interface{}, params Params) interface{} {
+	var res MatchResult
+	res.found(leaf, value, params)
+	return o.valueResolver(value, &res)
+}
+
+// WithConcurrentWrites guards the methods of a Pattern with a read-write
+// lock, so it can be modified, e.g. by a background config poller, while
+// requests are matched concurrently. It has no effect on a Router.
+// Default: disable
+func WithConcurrentWrites() Option {
+	return func(r *Options) {
+		r.concurrentWrites = true
+	}
+}
+
+// WithAllowEmptyParams lets an empty last segment match a named parameter
+// with an empty value, e.g. /users/ matches /users/:id with the id "", for
+// APIs where empty identifiers are legal. Empty segments in the middle of
+// the path, e.g. /users//posts for /users/:id/posts, always match.
+// Default: disable
+func WithAllowEmptyParams() Option {
+	return func(r *Options) {
+		r.allowEmptyParams = true
+	}
+}
+
+// WithMatchedRoutePathKey set the Param name under which the matched route
+// path is stored, if WithSaveMatchedRoutePath is enabled, instead of the
+// package-wide MatchedRoutePathParam, so routers can use different keys
+// without mutating the global. Params.MatchedRoutePath and the Params helpers
+// skipping the matched route path, e.g. Params.Equal, only know
+// MatchedRoutePathParam, so the params under the key are removed before, e.g.
+//
+//  ps.Without(router.MatchedRoutePathKey()).Equal(other)
+//
+// and the matched route path is read with ps.Param(router.MatchedRoutePathKey()).
+// Default: MatchedRoutePathParam
+func WithMatchedRoutePathKey(key string) Option {
+	if key == "" {
+		panic("matched route path key must not be empty")
+	}
+	return func(r *Options) {
+		r.matchedRoutePathKey = key
+	}
+}
+
+// MatchedRoutePathKey returns the Param name of the matched route path.
+// Default: MatchedRoutePathParam
+func (o *Options) MatchedRoutePathKey() string {
+	if o.matchedRoutePathKey != "" {
+		return o.matchedRoutePathKey
+	}
+	return MatchedRoutePathParam
+}
+
+// WithTrimCatchAllSlash strips the leading '/' of the catch-all values, e.g.
+// /files/templates/a.html matches /files/*filepath with the filepath
+// "templates/a.html" instead of "/templates/a.html".
+// The values of ParamsView, see Router.MatchView, are not trimmed.
+// Default: disable
+func WithTrimCatchAllSlash() Option {
+	return func(r *Options) {
+		r.trimCatchAllSlash = true
+	}
+}
+
+// TrimCatchAllSlash reports whether WithTrimCatchAllSlash is enabled.
+func (o *Options) TrimCatchAllSlash() bool {
+	return o.trimCatchAllSlash
+}
+
+// WithNotFoundValue set the value returned by the match functions, with
+// matched false, if no route matches, e.g. a default handler.
+// Default: nil
+func WithNotFoundValue(value interface{}) Option {
+	return func(r *Options) {
+		r.notFoundValue = value
+	}
+}
+
+// WithValueCodec set the codec used to (de)serialize the values of the route
+// table by Router.Export and Router.Import.
+// Default: StringCodec
+func WithValueCodec(c ValueCodec) Option {
+	return func(r *Options) {
+		r.valueCodec = c
+	}
+}
@@ -0,0 +1,346 @@
+package wrmatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clone returns a copy of ps which does not share its storage, e.g. to
+// retain the params passed to the callback of Pattern.MatchFunc.
+func (ps Params) Clone() Params {
+	if ps == nil {
+		return nil
+	}
+	return append(make(Params, 0, len(ps)), ps...)
+}
+
+// Without returns the params of ps whose key is not key, e.g. to drop the
+// matched route path stored under the key set with WithMatchedRoutePathKey
+// before comparing or converting the params. It returns ps itself, if no
+// param has the key.
+func (ps Params) Without(key string) Params {
+	for i, p := range ps {
+		if p.Key == key {
+			rest := append(make(Params, 0, len(ps)-1), ps[:i]...)
+			for _, p := range ps[i+1:] {
+				if p.Key != key {
+					rest = append(rest, p)
+				}
+			}
+			return rest
+		}
+	}
+	return ps
+}
+
+// Equal reports whether ps and other hold the same params, ignoring their
+// order and the matched route path under MatchedRoutePathParam, see Without.
+func (ps Params) Equal(other Params) bool {
+	count := make(map[Param]int, len(ps))
+	n := 0
+	for _, p := range ps {
+		if p.Key != MatchedRoutePathParam {
+			count[p]++
+			n++
+		}
+	}
+	for _, p := range other {
+		if p.Key == MatchedRoutePathParam {
+			continue
+		}
+		if count[p] == 0 {
+			return false
+		}
+		count[p]--
+		n--
+	}
+	return n == 0
+}
+
+// ParamDiff is a param whose value differs between two Params.
+type ParamDiff struct {
+	Key string
+	// Old is the value in the receiver of Diff, HasOld reports whether the
+	// key is present there.
+	Old    string
+	HasOld bool
+	// New is the value in the argument of Diff, HasNew reports whether the
+	// key is present there.
+	New    string
+	HasNew bool
+}
+
+// Diff returns the params which differ between ps and other ordered by key,
+// ignoring their order and the matched route path under
+// MatchedRoutePathParam, see Without. Like Param, only the first param of a
+// key is considered.
+func (ps Params) Diff(other Params) []ParamDiff {
+	diffs := make(map[string]*ParamDiff)
+	for _, p := range ps {
+		if _, ok := diffs[p.Key]; !ok && p.Key != MatchedRoutePathParam {
+			diffs[p.Key] = &ParamDiff{Key: p.Key, Old: p.Value, HasOld: true}
+		}
+	}
+	for _, p := range other {
+		if p.Key == MatchedRoutePathParam {
+			continue
+		}
+		d, ok := diffs[p.Key]
+		if !ok {
+			d = &ParamDiff{Key: p.Key}
+			diffs[p.Key] = d
+		} else if d.HasNew {
+			continue
+		}
+		d.New, d.HasNew = p.Value, true
+	}
+
+	var result []ParamDiff
+	for _, d := range diffs {
+		if d.HasOld != d.HasNew || d.Old != d.New {
+			result = append(result, *d)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Values returns the params as url.Values, so that form or query based code
+// can consume them. The matched route path under MatchedRoutePathParam is not
+// included, see Without.
+func (ps Params) Values() url.Values {
+	return ps.MergeValues(nil)
+}
+
+// MergeValues returns the params merged with the query params into a new
+// url.Values, the values of the params precede the query values of the same
+// key. The matched route path under MatchedRoutePathParam is not included,
+// see Without, query is not modified.
+func (ps Params) MergeValues(query url.Values) url.Values {
+	values := make(url.Values, len(ps)+len(query))
+	for _, p := range ps {
+		if p.Key != MatchedRoutePathParam {
+			values[p.Key] = append(values[p.Key], p.Value)
+		}
+	}
+	for key, vs := range query {
+		values[key] = append(values[key], vs...)
+	}
+	return values
+}
+
+// ToMap returns the params as a map, e.g. for logging or templating. Like
+// Param, only the first param of a key is included. The matched route path
+// under MatchedRoutePathParam is only included, if withMatchedRoutePath is
+// true, see Without.
+func (ps Params) ToMap(withMatchedRoutePath bool) map[string]string {
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		if p.Key == MatchedRoutePathParam && !withMatchedRoutePath {
+			continue
+		}
+		if _, ok := m[p.Key]; !ok {
+			m[p.Key] = p.Value
+		}
+	}
+	return m
+}
+
+// MarshalJSON encodes the params as a JSON object in the order of the
+// params, so the encoding is deterministic. Like Param, only the first param
+// of a key is encoded. The matched route path is encoded like other params.
+func (ps Params) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	seen := make(map[string]bool, len(ps))
+	for _, p := range ps {
+		if seen[p.Key] {
+			continue
+		}
+		seen[p.Key] = true
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(p.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the params from a JSON object in the order of its
+// members, the inverse of MarshalJSON. null decodes to nil params.
+func (ps *Params) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		*ps = nil
+		return nil
+	}
+	if tok != json.Delim('{') {
+		return errors.New("params must be a JSON object")
+	}
+	params := Params{}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		var value string
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		params = append(params, Param{key.(string), value})
+	}
+	*ps = params
+	return nil
+}
+
+// String formats the params as space separated key=value pairs in the order
+// of the params, e.g. for logging. All params are included, even if a key
+// is repeated. Values which are empty or contain spaces, quotes, '=' or
+// unprintable characters are quoted.
+func (ps Params) String() string {
+	var sb strings.Builder
+	for i, p := range ps {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(p.Key)
+		sb.WriteByte('=')
+		if needsQuote(p.Value) {
+			sb.WriteString(strconv.Quote(p.Value))
+		} else {
+			sb.WriteString(p.Value)
+		}
+	}
+	return sb.String()
+}
+
+// needsQuote reports whether the value must be quoted by Params.String.
+func needsQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, c := range value {
+		if c == ' ' || c == '"' || c == '=' || !strconv.IsPrint(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrMissingParam is the error of a ParamError for a missing param.
+var ErrMissingParam = errors.New("missing param")
+
+// ParamError is the error of the typed param accessors, e.g. Params.Int, if
+// the param is missing or its value can not be converted.
+type ParamError struct {
+	Key   string
+	Value string
+	// Err is ErrMissingParam or the conversion error, e.g. a *strconv.NumError.
+	Err error
+}
+
+func (e *ParamError) Error() string {
+	return "param '" + e.Key + "': " + e.Err.Error()
+}
+
+// Unwrap returns the error of the conversion.
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// convert converts the value of the first param with the given key with fn.
+func (ps Params) convert(name string, fn func(value string) error) error {
+	value, ok := ps.Get(name)
+	if !ok {
+		return &ParamError{name, "", ErrMissingParam}
+	}
+	if err := fn(value); err != nil {
+		return &ParamError{name, value, err}
+	}
+	return nil
+}
+
+// Int returns the value of the param with the given name as int.
+func (ps Params) Int(name string) (int, error) {
+	var i int
+	err := ps.convert(name, func(value string) (err error) {
+		i, err = strconv.Atoi(value)
+		return err
+	})
+	return i, err
+}
+
+// Int64 returns the value of the param with the given name as int64.
+func (ps Params) Int64(name string) (int64, error) {
+	var i int64
+	err := ps.convert(name, func(value string) (err error) {
+		i, err = strconv.ParseInt(value, 10, 64)
+		return err
+	})
+	return i, err
+}
+
+// Uint returns the value of the param with the given name as uint.
+func (ps Params) Uint(name string) (uint, error) {
+	var u uint64
+	err := ps.convert(name, func(value string) (err error) {
+		u, err = strconv.ParseUint(value, 10, strconv.IntSize)
+		return err
+	})
+	return uint(u), err
+}
+
+// Bool returns the value of the param with the given name as bool, see
+// strconv.ParseBool for the accepted values.
+func (ps Params) Bool(name string) (bool, error) {
+	var b bool
+	err := ps.convert(name, func(value string) (err error) {
+		b, err = strconv.ParseBool(value)
+		return err
+	})
+	return b, err
+}
+
+// Float returns the value of the param with the given name as float64.
+func (ps Params) Float(name string) (float64, error) {
+	var f float64
+	err := ps.convert(name, func(value string) (err error) {
+		f, err = strconv.ParseFloat(value, 64)
+		return err
+	})
+	return f, err
+}
+
+// Time returns the value of the param with the given name parsed with the
+// given layout, e.g. time.RFC3339 or "2006-01-02".
+func (ps Params) Time(name, layout string) (time.Time, error) {
+	var t time.Time
+	err := ps.convert(name, func(value string) (err error) {
+		t, err = time.Parse(layout, value)
+		return err
+	})
+	return t, err
+}
@@ -0,0 +1,26 @@
+//go:build go1.23
+// +build go1.23
+
+package wrmatch
+
+import "iter"
+
+// All returns an iterator over the keys and values of the params in order,
+// the matched route path under MatchedRoutePathParam is skipped, see Without,
+// e.g.
+//
+//  for key, value := range ps.All() {
+//  	log.Println(key, value)
+//  }
+func (ps Params) All() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		for _, p := range ps {
+			if p.Key == MatchedRoutePathParam {
+				continue
+			}
+			if !yield(p.Key, p.Value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+//go:build go1.23
+// +build go1.23
+
+package wrmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamsAll(t *testing.T) {
+	ps := Params{{"name", "gopher"}, {MatchedRoutePathParam, "/user/:name/:tab"}, {"tab", "about"}}
+
+	var keys, values []string
+	for key, value := range ps.All() {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	require.Equal(t, []string{"name", "tab"}, keys)
+	require.Equal(t, []string{"gopher", "about"}, values)
+
+	keys = nil
+	for key := range ps.All() {
+		keys = append(keys, key)
+		break
+	}
+	require.Equal(t, []string{"name"}, keys)
+
+	for range Params(nil).All() {
+		t.Fatal("nil params must not yield")
+	}
+}
@@ -0,0 +1,175 @@
+package wrmatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParamsClone(t *testing.T) {
+	ps := Params{{"name", "gopher"}}
+	c := ps.Clone()
+	require.Equal(t, ps, c)
+	c[0].Value = "other"
+	require.Equal(t, "gopher", ps[0].Value)
+	require.Nil(t, Params(nil).Clone())
+	require.NotNil(t, Params{}.Clone())
+}
+
+func TestParamsWithout(t *testing.T) {
+	ps := Params{{"a", "1"}, {"b", "2"}, {"a", "3"}}
+	require.Equal(t, Params{{"b", "2"}}, ps.Without("a"))
+	require.Equal(t, Params{{"a", "1"}, {"b", "2"}, {"a", "3"}}, ps)
+	require.Equal(t, ps, ps.Without("c"))
+	require.Nil(t, Params(nil).Without("a"))
+}
+
+func TestParamsEqual(t *testing.T) {
+	ps := Params{{"a", "1"}, {"b", "2"}, {MatchedRoutePathParam, "/:a/:b"}}
+
+	require.True(t, ps.Equal(Params{{"b", "2"}, {"a", "1"}}))
+	require.True(t, Params(nil).Equal(Params{{MatchedRoutePathParam, "/"}}))
+	require.False(t, ps.Equal(Params{{"a", "1"}}))
+	require.False(t, ps.Equal(Params{{"a", "1"}, {"b", "3"}}))
+	require.False(t, ps.Equal(Params{{"a", "1"}, {"b", "2"}, {"c", "3"}}))
+	require.False(t, Params{{"a", "1"}, {"a", "1"}}.Equal(Params{{"a", "1"}, {"b", "1"}}))
+}
+
+func TestParamsDiff(t *testing.T) {
+	ps := Params{{"a", "1"}, {"b", "2"}, {"c", "3"}, {MatchedRoutePathParam, "/:a/:b/:c"}}
+	other := Params{{"d", "4"}, {"c", "3"}, {"a", "0"}, {"a", "1"}}
+
+	require.Equal(t, []ParamDiff{
+		{Key: "a", Old: "1", HasOld: true, New: "0", HasNew: true},
+		{Key: "b", Old: "2", HasOld: true},
+		{Key: "d", New: "4", HasNew: true},
+	}, ps.Diff(other))
+	require.Empty(t, ps.Diff(Params{{"c", "3"}, {"b", "2"}, {"a", "1"}}))
+}
+
+func TestParamsValues(t *testing.T) {
+	ps := Params{{"id", "1"}, {"tag", "a"}, {"tag", "b"}, {MatchedRoutePathParam, "/:id"}}
+
+	require.Equal(t, url.Values{"id": {"1"}, "tag": {"a", "b"}}, ps.Values())
+	require.Equal(t, url.Values{}, Params(nil).Values())
+
+	query := url.Values{"id": {"2"}, "q": {"x"}}
+	values := ps.MergeValues(query)
+	require.Equal(t, url.Values{"id": {"1", "2"}, "tag": {"a", "b"}, "q": {"x"}}, values)
+	require.Equal(t, "1", values.Get("id"))
+	require.Equal(t, url.Values{"id": {"2"}, "q": {"x"}}, query)
+}
+
+func TestParamsGet(t *testing.T) {
+	ps := Params{{"name", "gopher"}, {"tab", ""}, {"name", "other"}}
+
+	value, ok := ps.Get("name")
+	require.True(t, ok)
+	require.Equal(t, "gopher", value)
+
+	value, ok = ps.Get("tab")
+	require.True(t, ok)
+	require.Empty(t, value)
+	require.True(t, ps.Has("tab"))
+
+	value, ok = ps.Get("id")
+	require.False(t, ok)
+	require.Empty(t, value)
+	require.False(t, ps.Has("id"))
+	require.False(t, Params(nil).Has("id"))
+}
+
+func TestParamsTyped(t *testing.T) {
+	ps := Params{
+		{"id", "42"}, {"big", "9007199254740993"}, {"neg", "-1"},
+		{"on", "true"}, {"ratio", "0.5"}, {"day", "2021-03-04"},
+	}
+
+	i, err := ps.Int("id")
+	require.NoError(t, err)
+	require.Equal(t, 42, i)
+	i64, err := ps.Int64("big")
+	require.NoError(t, err)
+	require.Equal(t, int64(9007199254740993), i64)
+	u, err := ps.Uint("id")
+	require.NoError(t, err)
+	require.Equal(t, uint(42), u)
+	b, err := ps.Bool("on")
+	require.NoError(t, err)
+	require.True(t, b)
+	f, err := ps.Float("ratio")
+	require.NoError(t, err)
+	require.Equal(t, 0.5, f)
+	day, err := ps.Time("day", "2006-01-02")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), day)
+
+	_, err = ps.Int("missing")
+	require.EqualError(t, err, "param 'missing': missing param")
+	require.True(t, errors.Is(err, ErrMissingParam))
+
+	_, err = ps.Uint("neg")
+	var perr *ParamError
+	require.True(t, errors.As(err, &perr))
+	require.Equal(t, "neg", perr.Key)
+	require.Equal(t, "-1", perr.Value)
+	var nerr *strconv.NumError
+	require.True(t, errors.As(err, &nerr))
+
+	_, err = ps.Bool("id")
+	require.Error(t, err)
+	_, err = ps.Time("id", time.RFC3339)
+	require.Error(t, err)
+}
+
+func TestParamsToMap(t *testing.T) {
+	ps := Params{{"name", "gopher"}, {"tab", ""}, {"name", "other"}, {MatchedRoutePathParam, "/user/:name/:tab"}}
+	require.Equal(t, map[string]string{"name": "gopher", "tab": ""}, ps.ToMap(false))
+	require.Equal(t, map[string]string{
+		"name": "gopher", "tab": "", MatchedRoutePathParam: "/user/:name/:tab",
+	}, ps.ToMap(true))
+	require.Empty(t, Params(nil).ToMap(true))
+}
+
+func TestParamsMarshalJSON(t *testing.T) {
+	ps := Params{{"name", "gopher"}, {"id", "4\"2"}, {"name", "other"}, {MatchedRoutePathParam, "/user/:name/:id"}}
+	data, err := json.Marshal(ps)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"gopher","id":"4\"2","$matchedRoutePath":"/user/:name/:id"}`, string(data))
+
+	data, err = json.Marshal(struct{ Params Params }{})
+	require.NoError(t, err)
+	require.Equal(t, `{"Params":{}}`, string(data))
+}
+
+func TestParamsUnmarshalJSON(t *testing.T) {
+	var ps Params
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"gopher","id":"4\"2","a":""}`), &ps))
+	require.Equal(t, Params{{"name", "gopher"}, {"id", "4\"2"}, {"a", ""}}, ps)
+
+	data, err := json.Marshal(ps)
+	require.NoError(t, err)
+	var decoded Params
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, ps, decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`null`), &ps))
+	require.Nil(t, ps)
+	require.NoError(t, json.Unmarshal([]byte(`{}`), &ps))
+	require.Equal(t, Params{}, ps)
+	require.Error(t, json.Unmarshal([]byte(`["a"]`), &ps))
+	require.Error(t, json.Unmarshal([]byte(`{"a":1}`), &ps))
+}
+
+func TestParamsString(t *testing.T) {
+	ps := Params{{"name", "gopher"}, {"path", "/a b"}, {"name", ""}, {"q", "a=b"}}
+	require.Equal(t, `name=gopher path="/a b" name="" q="a=b"`, ps.String())
+	require.Equal(t, "", Params(nil).String())
+	require.Equal(t, "[name=gopher]", fmt.Sprintf("[%v]", Params{{"name", "gopher"}}))
+}
@@ -0,0 +1,80 @@
+package wrmatch
+
+// paramSpan is the location of a single wildcard value in the matched path.
+type paramSpan struct {
+	key        string
+	start, end int
+}
+
+// ParamsView is a read-only view of the URL parameters of a match.
+// Instead of copying every value into a Param, it only records the offsets
+// of the values in the matched path, the values are sliced on demand.
+// A ParamsView is meant to be read and dropped; use Params to convert it,
+// if the parameters must be retained.
+type ParamsView struct {
+	path        string
+	spans       []paramSpan
+	matchedPath string
+	// matchedKey is the Param name of the matched route path.
+	matchedKey string
+	// extra holds params which are not sliced from the path, e.g. the locale.
+	extra Params
+}
+
+// Len returns the number of parameters in the view.
+func (v ParamsView) Len() int {
+	return len(v.spans) + len(v.extra)
+}
+
+// Key returns the name of the i-th parameter.
+func (v ParamsView) Key(i int) string {
+	if i >= len(v.spans) {
+		return v.extra[i-len(v.spans)].Key
+	}
+	return v.spans[i].key
+}
+
+// Value returns the value of the i-th parameter.
+func (v ParamsView) Value(i int) string {
+	if i >= len(v.spans) {
+		return v.extra[i-len(v.spans)].Value
+	}
+	return v.path[v.spans[i].start:v.spans[i].end]
+}
+
+// Param returns the value of the first parameter which key matches the given name.
+// If no matching parameter is found, an empty string is returned.
+func (v ParamsView) Param(name string) string {
+	for _, s := range v.spans {
+		if s.key == name {
+			return v.path[s.start:s.end]
+		}
+	}
+	if v.matchedPath != "" && name == v.matchedKey {
+		return v.matchedPath
+	}
+	return v.extra.Param(name)
+}
+
+// MatchedRoutePath retrieves the path of the matched route.
+// Router.saveMatchedRoutePath must have been enabled when the respective
+// value was added, otherwise this function always returns an empty string.
+func (v ParamsView) MatchedRoutePath() string {
+	return v.matchedPath
+}
+
+// Params converts the view to Params, the matched route path is appended
+// like Router.Match does.
+func (v ParamsView) Params() Params {
+	if len(v.spans) == 0 && v.matchedPath == "" && len(v.extra) == 0 {
+		return nil
+	}
+	ps := make(Params, 0, len(v.spans)+len(v.extra)+1)
+	for _, s := range v.spans {
+		ps = append(ps, Param{s.key, v.path[s.start:s.end]})
+	}
+	if v.matchedPath != "" {
+		ps = append(ps, Param{v.matchedKey, v.matchedPath})
+	}
+	return append(ps, v.extra...)
+}
@@ -0,0 +1,24 @@
+package wrmatch
+
+// Partition splits the route table into several routers by the key fn
+// returns for each route, e.g. by owner or by prefix, for per-team deployment
+// or per-shard loading. Every partition has the options of r and the routes
+// keep their metadata, like the disabled state and cache hints.
+// Overlays are not partitioned.
+func (r *Router) Partition(fn func(route Route) string) map[string]*Router {
+	parts := make(map[string]*Router)
+	for _, method := range r.methods() {
+		r.trees[method].walk("", func(path string, n *node) bool {
+			key := fn(newRoute(method, path, n))
+			p := parts[key]
+			if p == nil {
+				p = &Router{Options: r.Options}
+				parts[key] = p
+			}
+			p.add(method, path, unwrapValue(n.value))
+			p.copyMeta(method, path, n)
+			return true
+		})
+	}
+	return parts
+}
@@ -0,0 +1,47 @@
+package wrmatch
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterPartition(t *testing.T) {
+	router := New(WithSaveMatchedRoutePath())
+	router.GET("/user/:name", "user")
+	router.POST("/user/:name", "user-create")
+	router.GET("/team/:id", "team")
+	router.GET("/team/:id/members", "members")
+	router.Disable(http.MethodGet, "/team/:id/members")
+	router.SetCacheHint(http.MethodGet, "/user/:name", CacheHint{Cacheable: true, TTL: time.Minute})
+
+	parts := router.Partition(func(route Route) string {
+		return strings.SplitN(route.Path[1:], "/", 2)[0]
+	})
+	require.Len(t, parts, 2)
+
+	user := parts["user"]
+	require.Equal(t, []Route{
+		{http.MethodGet, "/user/:name", "user", false, ""},
+		{http.MethodPost, "/user/:name", "user-create", false, ""},
+	}, user.Routes())
+	v, ps, matched := user.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, "/user/:name", ps.MatchedRoutePath())
+	hint, ok := user.CacheHint(http.MethodGet, "/user/:name")
+	require.True(t, ok)
+	require.Equal(t, time.Minute, hint.TTL)
+
+	team := parts["team"]
+	require.True(t, team.IsDisabled(http.MethodGet, "/team/:id/members"))
+	_, _, matched = team.Match(http.MethodGet, "/user/gopher")
+	require.False(t, matched)
+
+	// the partitions are independent of the router
+	team.Enable(http.MethodGet, "/team/:id/members")
+	require.True(t, router.IsDisabled(http.MethodGet, "/team/:id/members"))
+}
@@ -123,6 +123,76 @@ func CleanPath(p string) string {
 	return string(buf[:w])
 }
 
+// CleanPathBuf appends the result of CleanPath(p) to dst and returns the
+// extended buffer. In contrast to CleanPath it never allocates, if dst has
+// enough capacity.
+func CleanPathBuf(dst []byte, p string) []byte {
+	n := len(p)
+	start := len(dst)
+	dst = append(dst, '/')
+
+	r := 0
+	if n > 0 && p[0] == '/' {
+		r = 1
+	}
+	trailing := n > 1 && p[n-1] == '/'
+
+	for r < n {
+		switch {
+		case p[r] == '/':
+			// empty path element, trailing slash is added after the end
+			r++
+
+		case p[r] == '.' && r+1 == n:
+			trailing = true
+			r++
+
+		case p[r] == '.' && p[r+1] == '/':
+			// . element
+			r += 2
+
+		case p[r] == '.' && p[r+1] == '.' && (r+2 == n || p[r+2] == '/'):
+			// .. element: remove to last /
+			r += 3
+
+			if w := len(dst) - start; w > 1 {
+				// can backtrack
+				w--
+				for w > 1 && dst[start+w] != '/' {
+					w--
+				}
+				dst = dst[:start+w]
+			}
+
+		default:
+			// Real path element.
+			// Add slash if needed
+			if len(dst)-start > 1 {
+				dst = append(dst, '/')
+			}
+
+			// Copy element
+			for r < n && p[r] != '/' {
+				dst = append(dst, p[r])
+				r++
+			}
+		}
+	}
+
+	// Re-append trailing slash
+	if trailing && len(dst)-start > 1 {
+		dst = append(dst, '/')
+	}
+	return dst
+}
+
+// IsCleanPath reports whether p is already a canonical URL path,
+// i.e. CleanPath(p) == p. It does not allocate for paths of up to 128 bytes.
+func IsCleanPath(p string) bool {
+	var buf [128]byte
+	return string(CleanPathBuf(buf[:0], p)) == p
+}
+
 // Internal helper to lazily create a buffer if necessary.
 // Calls to this function get inlined.
 func bufApp(buf *[]byte, s string, w int, c byte) {
@@ -77,6 +77,40 @@ func TestPathClean(t *testing.T) {
 	}
 }
 
+func TestCleanPathBuf(t *testing.T) {
+	buf := []byte("prefix")
+	for _, test := range cleanTests {
+		if s := string(CleanPathBuf(buf, test.path)[len(buf):]); s != test.result {
+			t.Errorf("CleanPathBuf(%q) = %q, want %q", test.path, s, test.result)
+		}
+	}
+	if s := string(CleanPathBuf(buf, "a/../b")); s != "prefix/b" {
+		t.Errorf("CleanPathBuf appended %q, want %q", s, "prefix/b")
+	}
+
+	dst := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() { CleanPathBuf(dst[:0], "/abc/../def//ghi/") })
+	if allocs > 0 {
+		t.Errorf("CleanPathBuf: %v allocs, want zero", allocs)
+	}
+}
+
+func TestIsCleanPath(t *testing.T) {
+	for _, test := range cleanTests {
+		if !IsCleanPath(test.result) {
+			t.Errorf("IsCleanPath(%q) = false, want true", test.result)
+		}
+		if want := test.path == test.result; IsCleanPath(test.path) != want {
+			t.Errorf("IsCleanPath(%q) = %v, want %v", test.path, !want, want)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { IsCleanPath("/abc/../def//ghi/") })
+	if allocs > 0 {
+		t.Errorf("IsCleanPath: %v allocs, want zero", allocs)
+	}
+}
+
 func TestPathCleanMallocs(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping malloc count in short mode")
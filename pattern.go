@@ -1,8 +1,16 @@
 package wrmatch
 
+import "sync"
+
 // Pattern is a via configurable url pattern
 type Pattern struct {
-	root *node
+	// mu guards the pattern, if WithConcurrentWrites is enabled
+	mu        sync.RWMutex
+	root      *node
+	paramsNew func() *Params
+	// paramsPool holds the params buffers of MatchFunc
+	paramsPool sync.Pool
+	maxParams  uint16
 	Options
 }
 
@@ -12,8 +20,9 @@ func NewPattern(opts ...Option) *Pattern {
 	r := &Pattern{
 		root: new(node),
 		Options: Options{
-			redirectTrailingSlash: true,
-			redirectFixedPath:     true,
+			redirectTrailingSlash:   true,
+			redirectCleanPath:       true,
+			redirectCaseInsensitive: true,
 		},
 	}
 	for _, opt := range opts {
@@ -24,49 +33,367 @@ func NewPattern(opts ...Option) *Pattern {
 
 // Add registers a new request value with the given path.
 func (r *Pattern) Add(path string, value interface{}) *Pattern {
+	if r.concurrentWrites {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	r.add(r.convertPath(path), value)
+	return r
+}
+
+func (r *Pattern) add(path string, value interface{}) {
 	if len(path) < 1 || path[0] != '/' {
 		panic("path must begin with '/' in path '" + path + "'")
 	}
 	if value == nil {
 		panic("value must not be nil")
 	}
+	path = nameWildcards(path)
+	r.checkConstraints(path)
+	if routes := expandRoutes(path); len(routes) > 1 || routes[0].path != path {
+		for _, route := range routes {
+			r.add(route.path, value)
+			if len(route.params) > 0 {
+				meta := r.root.findRoute(route.path).routeMeta()
+				meta.params = append(meta.params, route.params...)
+				paramsCount := countParams(route.path) + uint16(len(meta.params))
+				if r.saveMatchedRoutePath {
+					paramsCount++
+				}
+				if paramsCount > r.maxParams {
+					r.maxParams = paramsCount
+				}
+			}
+		}
+		return
+	}
 
+	varsCount := uint16(0)
 	if r.saveMatchedRoutePath {
 		value = matchValue{path, value}
+		varsCount++
 	}
 	r.root.addRoute(path, value)
+	r.root.findRoute(path).routeMeta().route = path
+
+	// Update maxParams
+	if paramsCount := countParams(path); paramsCount+varsCount > r.maxParams {
+		r.maxParams = paramsCount + varsCount
+	}
+
+	// Lazy-init paramsNew alloc func
+	if r.paramsNew == nil && r.maxParams > 0 {
+		r.paramsNew = func() *Params {
+			ps := make(Params, 0, r.maxParams)
+			return &ps
+		}
+	}
+}
+
+// Update replaces the value registered with the given path, which must be
+// the registered pattern, e.g. "/user/:name", not a request path, like for
+// Router.Update. It reports whether a value was registered for any variant of
+// the path, nothing is added otherwise.
+func (r *Pattern) Update(path string, value interface{}) bool {
+	if r.concurrentWrites {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	return r.update(path, value)
+}
+
+func (r *Pattern) update(path string, value interface{}) bool {
+	if value == nil {
+		panic("value must not be nil")
+	}
+	updated := false
+	for _, path := range routePaths(path) {
+		n := r.root.findRoute(path)
+		if n == nil {
+			continue
+		}
+		if r.saveMatchedRoutePath {
+			n.value = matchValue{path, value}
+		} else {
+			n.value = value
+		}
+		updated = true
+	}
+	return updated
+}
+
+// Put adds the value with the given path like Add, or replaces the value if
+// the path is registered already, like Update.
+func (r *Pattern) Put(path string, value interface{}) *Pattern {
+	if r.concurrentWrites {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	path = r.convertPath(path)
+	if !r.update(path, value) {
+		r.add(path, value)
+	}
 	return r
 }
 
+// Remove deletes the value registered with the given path, which must be the
+// registered pattern, not a request path, like for Router.Remove. The tree is
+// rebuilt without the pattern and the maximum number of params is recomputed.
+// It reports whether a value was registered for any variant of the path.
+//
+// Not concurrency-safe, unless WithConcurrentWrites is enabled!
+func (r *Pattern) Remove(path string) bool {
+	if r.concurrentWrites {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+	}
+	return r.remove(path)
+}
+
+func (r *Pattern) remove(path string) bool {
+	removed := false
+	for _, path := range routePaths(path) {
+		if root, ok := r.root.remove(path); ok {
+			r.root = root
+			removed = true
+		}
+	}
+	if !removed {
+		return false
+	}
+	r.maxParams = 0
+	r.root.walk("", func(path string, n *node) bool {
+		paramsCount := countParams(path)
+		if _, ok := n.value.(matchValue); ok {
+			paramsCount++
+		}
+		if n.meta != nil {
+			paramsCount += uint16(len(n.meta.params))
+		}
+		if paramsCount > r.maxParams {
+			r.maxParams = paramsCount
+		}
+		return true
+	})
+	return true
+}
+
+// Lookup allows the manual lookup of a path like Router.Lookup, without
+// path correction. If the path was found, it returns the value and the url
+// params, with the params of the route appended like by Match, e.g. the
+// default values of the omitted optional parameters. Otherwise the third return value indicates whether a redirection
+// to the same path with an extra / without the trailing slash should be
+// performed, so the caller decides itself whether to retry.
+func (r *Pattern) Lookup(path string) (interface{}, Params, bool) {
+	if r.concurrentWrites {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+	leaf, ps, tsr := r.root.lookupFrom(path, 0, r.paramsNew, nil, &r.Options)
+	if leaf == nil {
+		return nil, nil, tsr
+	}
+	var params Params
+	if ps != nil {
+		r.fixParams(*ps)
+		params = *ps
+	}
+	if leaf.meta != nil && len(leaf.meta.params) > 0 {
+		params = append(params, leaf.meta.params...)
+	}
+	return unwrapValue(leaf.value), params, false
+}
+
+// Match matches path and returns the value and the url params, the matched
+// route path is appended to the params if saveMatchedRoutePath is enabled.
+func (r *Pattern) Match(path string) (interface{}, Params, bool) {
+	if r.concurrentWrites {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+	leaf, ps := r.match(path, r.paramsNew)
+	if leaf == nil {
+		return r.notFoundValue, nil, false
+	}
+	value, ps := r.value(leaf, ps)
+	return value, ps, true
+}
+
+// MatchFunc matches path like Match, but passes the value and the url params
+// to fn instead of returning them, and returns the result of fn, or false if
+// nothing matches. The params are pooled, so they are only valid until fn
+// returns, see Params.Clone, and matching does not allocate in the steady
+// state.
+func (r *Pattern) MatchFunc(path string, fn func(value interface{}, ps Params) bool) bool {
+	if r.concurrentWrites {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+	if r.paramsNew == nil {
+		leaf, _ := r.match(path, nil)
+		if leaf == nil {
+			return false
+		}
+		value, ps := r.value(leaf, nil)
+		return fn(value, ps)
+	}
+
+	buf := r.getParams()
+	defer r.paramsPool.Put(buf)
+	leaf, ps := r.match(path, buf.get)
+	if leaf == nil {
+		return false
+	}
+	value, ps := r.value(leaf, ps)
+	return fn(value, ps)
+}
+
+// value returns the value of the matched leaf and the url params, with the
+// matched route path appended, if saveMatchedRoutePath is enabled.
+func (r *Pattern) value(leaf *node, ps Params) (interface{}, Params) {
+	value := leaf.value
+	r.fixParams(ps)
+	if r.saveMatchedRoutePath {
+		vv, ok := value.(matchValue)
+		if !ok {
+			panic("enabled saveMatchedRoutePath, value should be struct(matchValue)")
+		}
+		value = vv.Value
+		ps = append(ps, Param{r.MatchedRoutePathKey(), vv.matchedPath})
+	}
+	if leaf.meta != nil && len(leaf.meta.params) > 0 {
+		ps = append(ps, leaf.meta.params...)
+	}
+	if r.valueResolver != nil {
+		value = r.resolveValue(leaf, value, ps)
+	}
+	return value, ps
+}
+
+// paramsBuf is a pooled params buffer of MatchFunc.
+type paramsBuf struct {
+	ps Params
+	// get returns the buffer, it is passed to match as the params
+	// allocation func.
+	get func() *Params
+}
+
+// getParams returns an empty params buffer of at least maxParams capacity.
+func (r *Pattern) getParams() *paramsBuf {
+	if buf, ok := r.paramsPool.Get().(*paramsBuf); ok && cap(buf.ps) >= int(r.maxParams) {
+		buf.ps = buf.ps[:0]
+		return buf
+	}
+	buf := &paramsBuf{ps: make(Params, 0, r.maxParams)}
+	buf.get = func() *Params { return &buf.ps }
+	return buf
+}
+
 // MatchURL match method and path return matched or not and store value and matched route path if Router.saveMatchedRoutePath enabled.
 func (r *Pattern) MatchURL(path string) (interface{}, string, bool) {
-	value, _, tsr := r.root.getValue(path, nil)
-	if value != nil {
-		if r.saveMatchedRoutePath {
-			vv, ok := value.(matchValue)
-			if !ok {
-				panic("enabled saveMatchedRoutePath, value should be struct(matchValue)")
-			}
-			return vv.Value, vv.matchedPath, true
+	if r.concurrentWrites {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+	leaf, _ := r.match(path, nil)
+	if leaf == nil {
+		return r.notFoundValue, "", false
+	}
+	value, matchedPath := leaf.value, ""
+	if r.saveMatchedRoutePath {
+		vv, ok := value.(matchValue)
+		if !ok {
+			panic("enabled saveMatchedRoutePath, value should be struct(matchValue)")
 		}
-		return value, "", true
+		value, matchedPath = vv.Value, vv.matchedPath
 	}
-	if path != "/" {
+	if r.valueResolver != nil {
+		value = r.resolveValue(leaf, value, nil)
+	}
+	return value, matchedPath, true
+}
+
+// match returns the node holding the value for path, following the enabled
+// path corrections, and the url params if paramsNew is not nil. The
+// corrected path is matched exactly, it is never corrected again.
+func (r *Pattern) match(path string, paramsNew func() *Params) (*node, Params) {
+	leaf, ps, tsr := r.root.lookupFrom(path, 0, paramsNew, nil, &r.Options)
+	if leaf == nil && path != "/" {
+		if ps != nil {
+			// reuse the params of the failed lookup for the corrected path
+			*ps = (*ps)[:0]
+			spare := ps
+			paramsNew = func() *Params { return spare }
+		}
 		if tsr && r.redirectTrailingSlash {
 			if len(path) > 1 && path[len(path)-1] == '/' {
 				path = path[:len(path)-1]
 			} else {
 				path += "/"
 			}
-			return r.MatchURL(path)
+			leaf, ps, _ = r.root.lookupFrom(path, 0, paramsNew, nil, &r.Options)
+		} else if fixedPath, found := r.fixPath(r.root, path); found && fixedPath != path {
+			// Try to fix the request path
+			leaf, ps, _ = r.root.lookupFrom(fixedPath, 0, paramsNew, nil, &r.Options)
 		}
-		// Try to fix the request path
-		if r.redirectFixedPath {
-			fixedPath, found := r.root.findCaseInsensitivePath(CleanPath(path), r.redirectTrailingSlash)
-			if found {
-				return r.MatchURL(fixedPath)
-			}
+	}
+	if leaf == nil || ps == nil {
+		return leaf, nil
+	}
+	return leaf, *ps
+}
+
+// Walk calls fn for every registered pattern with the pattern and the value,
+// in lexical order of the patterns. Walk stops as soon as fn returns false.
+// If WithConcurrentWrites is enabled, fn must not modify the pattern.
+func (r *Pattern) Walk(fn func(path string, value interface{}) bool) {
+	if r.concurrentWrites {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+	r.root.walk("", func(path string, n *node) bool {
+		return fn(path, unwrapValue(n.value))
+	})
+}
+
+// PatternEntry describes a registered pattern.
+type PatternEntry struct {
+	Path  string
+	Value interface{}
+}
+
+// List returns a snapshot of all registered patterns in the same order as Walk.
+func (r *Pattern) List() []PatternEntry {
+	var entries []PatternEntry
+	r.Walk(func(path string, value interface{}) bool {
+		entries = append(entries, PatternEntry{path, value})
+		return true
+	})
+	return entries
+}
+
+// Clone returns a deep copy of the pattern, which can be modified without
+// affecting the pattern, e.g. to build the next version of a live pattern and
+// swap it in atomically, so readers never need to take a lock.
+func (r *Pattern) Clone() *Pattern {
+	if r.concurrentWrites {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+	}
+	return r.clone()
+}
+
+func (r *Pattern) clone() *Pattern {
+	c := &Pattern{
+		root:      r.root.clone(),
+		maxParams: r.maxParams,
+		Options:   r.Options,
+	}
+	if c.maxParams > 0 {
+		c.paramsNew = func() *Params {
+			ps := make(Params, 0, c.maxParams)
+			return &ps
 		}
 	}
-	return nil, "", false
+	return c
 }
@@ -2,6 +2,8 @@ package wrmatch
 
 import (
 	"net/http"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -152,3 +154,281 @@ func TestPatternEnableSaveMatchedRouterPathPanicShouldNotHappen(t *testing.T) {
 		router.MatchURL("/user/gopher")
 	})
 }
+
+func TestPatternValueResolver(t *testing.T) {
+	router := NewPattern(WithValueResolver(func(stored interface{}, res *MatchResult) interface{} {
+		return res.Route + "=" + stored.(string)
+	}))
+	router.Add("/user/:name", "handle1")
+
+	v, _, matched := router.MatchURL("/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "/user/:name=handle1", v)
+}
+
+func TestPatternMatchParams(t *testing.T) {
+	pattern := NewPattern()
+	pattern.Add("/user/:name", "user")
+	pattern.Add("/user/:name/files/*filepath", "files")
+	pattern.Add("/static", "static")
+
+	v, ps, matched := pattern.Match("/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+	v, ps, matched = pattern.Match("/USER/gopher/files/a/B")
+	require.True(t, matched)
+	require.Equal(t, "files", v)
+	require.Equal(t, Params{Param{"name", "gopher"}, Param{"filepath", "/a/B"}}, ps)
+	v, ps, matched = pattern.Match("/user/gopher/")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+	v, ps, matched = pattern.Match("/static")
+	require.True(t, matched)
+	require.Equal(t, "static", v)
+	require.Nil(t, ps)
+	_, _, matched = pattern.Match("/missing")
+	require.False(t, matched)
+
+	pattern = NewPattern(WithSaveMatchedRoutePath())
+	pattern.Add("/user/:name", "user")
+	_, ps, matched = pattern.Match("/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "gopher", ps.Param("name"))
+	require.Equal(t, "/user/:name", ps.MatchedRoutePath())
+}
+
+func TestPatternRemoveUpdate(t *testing.T) {
+	pattern := NewPattern()
+	pattern.Add("/user/:name", "user")
+	pattern.Add("/user/:name/files/*filepath", "files")
+	pattern.Add("/static", "static")
+
+	require.True(t, pattern.Update("/user/:name", "user2"))
+	require.False(t, pattern.Update("/user/gopher", "x"))
+	v, _, matched := pattern.Match("/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user2", v)
+
+	pattern.Put("/static", "static2").Put("/new", "new")
+	v, _, _ = pattern.Match("/static")
+	require.Equal(t, "static2", v)
+	v, _, _ = pattern.Match("/new")
+	require.Equal(t, "new", v)
+
+	require.True(t, pattern.Remove("/user/:name/files/*filepath"))
+	require.False(t, pattern.Remove("/user/:name/files/*filepath"))
+	_, _, matched = pattern.Match("/user/gopher/files/a")
+	require.False(t, matched)
+	require.Equal(t, uint16(1), pattern.maxParams)
+	_, ps, matched := pattern.Match("/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+
+	// the removed paths can be added again with another wildcard
+	require.True(t, pattern.Remove("/user/:name"))
+	pattern.Add("/user/:id/files/*rest", "files")
+	_, ps, matched = pattern.Match("/user/gopher/files/a")
+	require.True(t, matched)
+	require.Equal(t, Params{Param{"id", "gopher"}, Param{"rest", "/a"}}, ps)
+}
+
+func TestPatternWalk(t *testing.T) {
+	pattern := NewPattern(WithSaveMatchedRoutePath())
+	require.Empty(t, pattern.List())
+	pattern.Add("/user/:name", "user")
+	pattern.Add("/static", "static")
+	pattern.Add("/user/:name/files/*filepath", "files")
+
+	require.Equal(t, []PatternEntry{
+		{"/static", "static"},
+		{"/user/:name", "user"},
+		{"/user/:name/files/*filepath", "files"},
+	}, pattern.List())
+
+	var paths []string
+	pattern.Walk(func(path string, _ interface{}) bool {
+		paths = append(paths, path)
+		return len(paths) < 2
+	})
+	require.Equal(t, []string{"/static", "/user/:name"}, paths)
+}
+
+func TestPatternLookup(t *testing.T) {
+	pattern := NewPattern()
+	pattern.Add("/user/:name", "user")
+	pattern.Add("/dir/", "dir")
+
+	v, ps, tsr := pattern.Lookup("/user/gopher")
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+	require.False(t, tsr)
+
+	v, ps, tsr = pattern.Lookup("/user/gopher/")
+	require.Nil(t, v)
+	require.Nil(t, ps)
+	require.True(t, tsr)
+	v, _, tsr = pattern.Lookup("/dir")
+	require.Nil(t, v)
+	require.True(t, tsr)
+
+	// no case-insensitive correction
+	v, _, tsr = pattern.Lookup("/USER/gopher")
+	require.Nil(t, v)
+	require.False(t, tsr)
+}
+
+func TestPatternConcurrentWrites(t *testing.T) {
+	pattern := NewPattern(WithConcurrentWrites())
+	pattern.Add("/block/:id", "blocked")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				path := "/p" + strconv.Itoa(i) + "/" + strconv.Itoa(j)
+				pattern.Put(path, "v")
+				if j%2 == 0 {
+					pattern.Remove(path)
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v, ps, matched := pattern.Match("/block/42")
+				require.True(t, matched)
+				require.Equal(t, "blocked", v)
+				require.Equal(t, "42", ps.Param("id"))
+				pattern.List()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Len(t, pattern.List(), 1+4*50)
+}
+
+func TestPatternClone(t *testing.T) {
+	pattern := NewPattern()
+	pattern.Add("/user/:name", "user")
+	pattern.Add("/users", "users")
+
+	clone := pattern.Clone()
+	require.Equal(t, pattern.List(), clone.List())
+
+	clone.Add("/user/:name/:id/:tab", "tab")
+	clone.Remove("/users")
+	clone.Update("/user/:name", "member")
+
+	v, ps, matched := clone.Match("/user/gopher/1/about")
+	require.True(t, matched)
+	require.Equal(t, "tab", v)
+	require.Len(t, ps, 3)
+
+	// the pattern is unchanged
+	require.Equal(t, []PatternEntry{
+		{"/user/:name", "user"},
+		{"/users", "users"},
+	}, pattern.List())
+	_, _, matched = pattern.Match("/user/gopher/1/about")
+	require.False(t, matched)
+	require.Equal(t, uint16(1), pattern.maxParams)
+}
+
+func TestPatternMatchFunc(t *testing.T) {
+	pattern := NewPattern()
+	pattern.Add("/user/:name", "user")
+	pattern.Add("/static", "static")
+
+	var value interface{}
+	var ps Params
+	require.True(t, pattern.MatchFunc("/user/gopher/", func(v interface{}, params Params) bool {
+		value, ps = v, params.Clone()
+		return true
+	}))
+	require.Equal(t, "user", value)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+
+	require.False(t, pattern.MatchFunc("/user/gopher", func(v interface{}, params Params) bool {
+		return false
+	}))
+	require.False(t, pattern.MatchFunc("/unknown", func(v interface{}, params Params) bool {
+		t.Fatal("fn must not be called")
+		return true
+	}))
+	require.True(t, pattern.MatchFunc("/STATIC", func(v interface{}, params Params) bool {
+		return v == "static" && params == nil
+	}))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		pattern.MatchFunc("/user/gopher", func(v interface{}, params Params) bool {
+			return params.Param("name") == "gopher"
+		})
+	})
+	require.Zero(t, allocs)
+}
+
+func TestPatternOptionalSyntax(t *testing.T) {
+	pattern := NewPattern()
+	pattern.Add("/users/:id?", "users")
+	pattern.Add("/api(/v1)/items", "items")
+	pattern.Add("/posts/:page=1", "posts")
+	pattern.Add("/tags/:tag=all/:page=1", "tags")
+
+	tests := []struct {
+		path  string
+		value interface{}
+		ps    Params
+	}{
+		{"/users", "users", nil},
+		{"/users/7", "users", Params{{"id", "7"}}},
+		{"/api/items", "items", nil},
+		{"/api/v1/items", "items", nil},
+		{"/posts", "posts", Params{{"page", "1"}}},
+		{"/posts/3", "posts", Params{{"page", "3"}}},
+		{"/tags", "tags", Params{{"tag", "all"}, {"page", "1"}}},
+		{"/tags/go", "tags", Params{{"tag", "go"}, {"page", "1"}}},
+		{"/tags/go/2", "tags", Params{{"tag", "go"}, {"page", "2"}}},
+	}
+	for _, tt := range tests {
+		value, ps, matched := pattern.Match(tt.path)
+		require.True(t, matched, tt.path)
+		require.Equal(t, tt.value, value, tt.path)
+		require.Equal(t, tt.ps, ps, tt.path)
+
+		require.True(t, pattern.MatchFunc(tt.path, func(v interface{}, params Params) bool {
+			return v == tt.value && len(params) == len(tt.ps)
+		}), tt.path)
+
+		value, ps, _ = pattern.Lookup(tt.path)
+		require.Equal(t, tt.value, value, tt.path)
+		require.Equal(t, tt.ps, ps, tt.path)
+	}
+
+	paths := make([]string, 0)
+	pattern.Walk(func(path string, value interface{}) bool {
+		paths = append(paths, path)
+		return true
+	})
+	require.Equal(t, []string{
+		"/api/items", "/api/v1/items", "/posts", "/posts/:page",
+		"/tags", "/tags/:tag", "/tags/:tag/:page", "/users", "/users/:id",
+	}, paths)
+
+	require.True(t, pattern.Remove("/tags/:tag/:page"))
+	_, ps, matched := pattern.Match("/tags")
+	require.True(t, matched)
+	require.Equal(t, Params{{"tag", "all"}, {"page", "1"}}, ps)
+
+	pattern = NewPattern(WithSaveMatchedRoutePath())
+	pattern.Put("/posts/:page=1", "posts")
+	_, ps, matched = pattern.Match("/posts")
+	require.True(t, matched)
+	require.Equal(t, Params{{MatchedRoutePathParam, "/posts"}, {"page", "1"}}, ps)
+
+	require.Panics(t, func() { NewPattern().Add("/users/:id?/posts", "x") })
+	require.Panics(t, func() { NewPattern().Add("/api(/v1/items", "x") })
+}
@@ -0,0 +1,78 @@
+package wrmatch
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TablePolicy restricts the routes which can be added to a router, e.g. to
+// enforce the rules of a platform team on tables assembled from many
+// contributors. The zero value allows every route.
+type TablePolicy struct {
+	// NoRootCatchAll forbids catch-alls directly below the root, e.g.
+	// "/*path", which shadow every unregistered path.
+	NoRootCatchAll bool
+	// MaxRoutes caps the number of routes of all methods, 0 means unlimited.
+	MaxRoutes int
+	// MaxCatchAlls caps the number of catch-all routes, 0 means unlimited.
+	MaxCatchAlls int
+	// RequireParamConstraints requires a constraint on every named
+	// parameter, e.g. "/user/:id<int>".
+	RequireParamConstraints bool
+}
+
+// PolicyError is the error of a route violating the TablePolicy of a router.
+type PolicyError struct {
+	Method string
+	Path   string
+	// Reason describes the violation.
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return "route '" + e.Method + " " + e.Path + "' violates the policy: " + e.Reason
+}
+
+// WithPolicy set the policy the routes of a Router must comply with.
+// Router.Add panics with a *PolicyError for a violating route, Router.AddE,
+// Router.AddAll and BuildParallel return it.
+// Default: none
+func WithPolicy(p TablePolicy) Option {
+	return func(r *Options) {
+		r.policy = &p
+	}
+}
+
+// isCatchAll reports whether the route path has a catch-all.
+func isCatchAll(path string) bool {
+	for {
+		wildcard, i, _ := findWildcard(path)
+		if i < 0 {
+			return false
+		}
+		if wildcard[0] == '*' {
+			return true
+		}
+		path = path[i+len(wildcard):]
+	}
+}
+
+// check returns the violation of the policy by adding the route with method
+// and path to a table holding routes routes, catchAlls of them catch-alls.
+func (p *TablePolicy) check(method, path string, routes, catchAlls int) error {
+	if p.NoRootCatchAll && strings.HasPrefix(path, "/*") {
+		return &PolicyError{method, path, "catch-all at the root"}
+	}
+	if p.MaxRoutes > 0 && routes >= p.MaxRoutes {
+		return &PolicyError{method, path, "more than " + strconv.Itoa(p.MaxRoutes) + " routes"}
+	}
+	if p.MaxCatchAlls > 0 && isCatchAll(path) && catchAlls >= p.MaxCatchAlls {
+		return &PolicyError{method, path, "more than " + strconv.Itoa(p.MaxCatchAlls) + " catch-alls"}
+	}
+	if p.RequireParamConstraints {
+		if name := unconstrainedParam(path); name != "" {
+			return &PolicyError{method, path, "parameter '" + name + "' without constraint"}
+		}
+	}
+	return nil
+}
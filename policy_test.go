@@ -0,0 +1,41 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterPolicy(t *testing.T) {
+	router := New(WithPolicy(TablePolicy{NoRootCatchAll: true, MaxRoutes: 3, MaxCatchAlls: 1}))
+
+	err := router.AddE(http.MethodGet, "/*path", "root")
+	require.EqualError(t, err, "route 'GET /*path' violates the policy: catch-all at the root")
+	require.IsType(t, &PolicyError{}, err)
+
+	router.GET("/static/*filepath", "static")
+	require.EqualError(t, router.AddE(http.MethodGet, "/files/*filepath", "files"),
+		"route 'GET /files/*filepath' violates the policy: more than 1 catch-alls")
+	router.GET("/a", "a")
+	router.GET("/b", "b")
+	require.EqualError(t, router.AddE(http.MethodGet, "/c", "c"),
+		"route 'GET /c' violates the policy: more than 3 routes")
+	require.Panics(t, func() { router.GET("/c", "c") })
+
+	// removed routes free the quota
+	require.True(t, router.Remove(http.MethodGet, "/static/*filepath"))
+	require.NoError(t, router.AddE(http.MethodGet, "/files/*filepath", "files"))
+	require.Error(t, router.Clone().AddE(http.MethodGet, "/c", "c"))
+
+	_, err = BuildParallel([]Route{
+		{Method: http.MethodGet, Path: "/a", Value: "a"},
+		{Method: http.MethodGet, Path: "/b", Value: "b"},
+	}, 2, WithPolicy(TablePolicy{MaxRoutes: 1}))
+	require.EqualError(t, err, "route 'GET /b' violates the policy: more than 1 routes")
+
+	router = New(WithPolicy(TablePolicy{RequireParamConstraints: true}))
+	router.GET("/user/:id<int>/files/*filepath", "files")
+	require.EqualError(t, router.AddE(http.MethodGet, "/user/:id<int>/posts/:slug", "post"),
+		"route 'GET /user/:id<int>/posts/:slug' violates the policy: parameter 'slug' without constraint")
+}
@@ -0,0 +1,54 @@
+package wrmatch
+
+import "strings"
+
+// prefixOptions are the options of the paths starting with prefix.
+type prefixOptions struct {
+	prefix string
+	opts   *Options
+}
+
+// ConfigurePrefix overrides the path correction options of the router for all
+// request paths starting with the static prefix, e.g. to enable lenient
+// matching for a legacy section of the URL space only:
+//
+//  router := wrmatch.New(wrmatch.WithDisableRedirectTrailingSlash(), wrmatch.WithDisableRedirectFixedPath())
+//  router.ConfigurePrefix("/legacy/", wrmatch.WithRedirectTrailingSlash(), wrmatch.WithRedirectCaseInsensitive())
+//
+// The prefix is compared case insensitively, the longest configured prefix
+// wins. Only the redirect options are effective per prefix, the options start
+// from the options of the router at the time of the call.
+func (r *Router) ConfigurePrefix(prefix string, opts ...Option) *Router {
+	r.checkSealed()
+	if len(prefix) < 1 || prefix[0] != '/' {
+		panic("prefix must begin with '/' in prefix '" + prefix + "'")
+	}
+	if strings.ContainsAny(prefix, ":*") {
+		panic("prefix must not contain wildcards in prefix '" + prefix + "'")
+	}
+	o := r.Options
+	o.prefixes = nil
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// the prefixes are copied, since they are shared with clones of the router
+	prefixes := make([]prefixOptions, 0, len(r.prefixes)+1)
+	for _, p := range r.prefixes {
+		if p.prefix != prefix {
+			prefixes = append(prefixes, p)
+		}
+	}
+	r.prefixes = append(prefixes, prefixOptions{prefix, &o})
+	return r
+}
+
+// pathOptions returns the options of the request path.
+func (o *Options) pathOptions(path string) *Options {
+	opts, n := o, 0
+	for _, p := range o.prefixes {
+		if len(p.prefix) > n && len(path) >= len(p.prefix) && strings.EqualFold(path[:len(p.prefix)], p.prefix) {
+			opts, n = p.opts, len(p.prefix)
+		}
+	}
+	return opts
+}
@@ -0,0 +1,53 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterConfigurePrefix(t *testing.T) {
+	router := New(WithDisableRedirectTrailingSlash(), WithDisableRedirectFixedPath())
+	router.ConfigurePrefix("/legacy/", WithRedirectTrailingSlash(), WithRedirectCaseInsensitive())
+	router.ConfigurePrefix("/legacy/strict/", WithDisableRedirectCaseInsensitive())
+	router.GET("/legacy/page", "page")
+	router.GET("/legacy/dir/", "dir")
+	router.GET("/legacy/strict/page", "strict")
+	router.GET("/api/users", "users")
+
+	tests := []struct {
+		path    string
+		matched bool
+	}{
+		{"/legacy/page", true},
+		{"/legacy/PAGE", true},
+		{"/LEGACY/page", true},
+		{"/legacy/page/", true},
+		{"/legacy/dir", true},
+		{"/legacy/../legacy/page", false},
+		{"/legacy/strict/page/", false},
+		{"/legacy/strict/PAGE", false},
+		{"/api/users", true},
+		{"/api/USERS", false},
+		{"/api/users/", false},
+	}
+	for _, tt := range tests {
+		_, _, matched := router.Match(http.MethodGet, tt.path)
+		require.Equal(t, tt.matched, matched, tt.path)
+	}
+
+	res := router.MatchRedirect(http.MethodGet, "/legacy/PAGE")
+	require.Equal(t, "/legacy/page", res.Redirect)
+
+	// clones do not share the prefix options
+	clone := router.Clone()
+	clone.ConfigurePrefix("/legacy/", WithDisableRedirectFixedPath())
+	_, _, matched := router.Match(http.MethodGet, "/legacy/PAGE")
+	require.True(t, matched)
+	_, _, matched = clone.Match(http.MethodGet, "/legacy/PAGE")
+	require.False(t, matched)
+
+	require.Panics(t, func() { router.ConfigurePrefix("legacy") })
+	require.Panics(t, func() { router.ConfigurePrefix("/:legacy") })
+}
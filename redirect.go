@@ -0,0 +1,115 @@
+package wrmatch
+
+import (
+	"net/http"
+	"strings"
+)
+
+// MatchRedirect matches method and path like MatchResult, but does not match
+// corrected paths transparently. If no route matches the path exactly, but the
+// path would be corrected according to the enabled redirect options, the
+// corrected path and the suggested status code are returned in
+// MatchResult.Redirect and MatchResult.RedirectCode and Matched is false.
+// This allows the HTTP layer to issue a real redirect instead of serving
+// content at the wrong URL.
+func (r *Router) MatchRedirect(method, path string) MatchResult {
+	res := MatchResult{Value: r.notFoundValue}
+	method = r.normalizeMethod(method)
+	exact, fixedPath := r.resolveLocale(method, path)
+	if !exact {
+		if method != http.MethodConnect && path != "/" {
+			_, res.TSR = r.tsr(method, path)
+		}
+		if fixedPath == "" {
+			if value, ok := r.autoOptionsValue(method, path); ok {
+				res.Value, res.Matched = value, true
+				return res
+			}
+			res.MethodNotAllowed = len(r.AllowedMethods(path)) > 0
+			return res
+		}
+		res.Redirect = fixedPath
+		res.RedirectCode = http.StatusMovedPermanently
+		if method != http.MethodGet {
+			res.RedirectCode = http.StatusPermanentRedirect
+		}
+		return res
+	}
+
+	leaf, value, ps := r.match(method, path, r.paramsNew, nil)
+	res.found(leaf, value, ps)
+	return res
+}
+
+// resolveLocale is resolve for the path without the locale prefix, if there
+// is one, like match. The corrected path keeps the locale prefix.
+func (r *Router) resolveLocale(method, path string) (exact bool, fixedPath string) {
+	if _, rest, ok := r.splitLocale(path); ok {
+		if exact, fixedPath = r.resolve(method, rest); exact {
+			return true, ""
+		}
+		if fixedPath != "" {
+			return false, strings.TrimSuffix(path, rest) + fixedPath
+		}
+	}
+	return r.resolve(method, path)
+}
+
+// resolve reports whether match finds a route for method and path without
+// correcting the path, otherwise it returns the corrected path match follows,
+// or an empty string if there is none.
+func (r *Router) resolve(method, path string) (exact bool, fixedPath string) {
+	if leaf, _, _, _ := r.matchExact(method, path, nil, nil); leaf != nil {
+		return true, ""
+	}
+	return false, r.resolveCorrected(method, path)
+}
+
+// resolveCorrected returns the corrected path matchCorrected follows, or an
+// empty string if there is none.
+func (r *Router) resolveCorrected(method, path string) string {
+	for i := len(r.overlays) - 1; i >= 0; i-- {
+		if fixedPath := r.overlays[i].resolveCorrected(method, path); fixedPath != "" {
+			return fixedPath
+		}
+	}
+	if fixedPath := r.resolveTree(method, method, path); fixedPath != "" {
+		return fixedPath
+	}
+	if method != MethodAny && r.isAnyMethod(method) {
+		return r.resolveTree(MethodAny, method, path)
+	}
+	return ""
+}
+
+// resolveTree is resolveCorrected for the tree stored under key, like
+// correctTree it never corrects the corrected path again.
+func (r *Router) resolveTree(key, method, path string) string {
+	root := r.trees[key]
+	if root == nil {
+		return ""
+	}
+	_, _, tsr := r.lookup(key, root, path, nil, nil)
+	if method == http.MethodConnect || path == "/" {
+		return ""
+	}
+	opts := r.pathOptions(path)
+	if tsr && opts.redirectTrailingSlash {
+		if len(path) > 1 && path[len(path)-1] == '/' {
+			path = path[:len(path)-1]
+		} else {
+			path += "/"
+		}
+		if leaf, _, _, _ := r.matchExact(method, path, nil, nil); leaf == nil || leaf.strictSlash() {
+			return ""
+		}
+		return path
+	}
+	if fixedPath, found := opts.fixPath(root, path); found && fixedPath != path {
+		if leaf, _, _, _ := r.matchExact(method, fixedPath, nil, nil); leaf == nil || leaf.noFixedPath() {
+			return ""
+		}
+		return fixedPath
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterMatchRedirect(t *testing.T) {
+	router := New()
+	router.GET("/path", "path")
+	router.GET("/dir/", "dir")
+	router.GET("/user/:name", "user")
+	router.POST("/post/", "post")
+
+	tests := []struct {
+		method   string
+		path     string
+		matched  bool
+		redirect string
+		code     int
+	}{
+		{http.MethodGet, "/path", true, "", 0},
+		{http.MethodGet, "/user/gopher", true, "", 0},
+		{http.MethodGet, "/path/", false, "/path", http.StatusMovedPermanently},
+		{http.MethodGet, "/dir", false, "/dir/", http.StatusMovedPermanently},
+		{http.MethodGet, "/PATH", false, "/path", http.StatusMovedPermanently},
+		{http.MethodGet, "/DIR", false, "/dir/", http.StatusMovedPermanently},
+		{http.MethodGet, "/../path", false, "/path", http.StatusMovedPermanently},
+		{http.MethodGet, "/USER/gopher", false, "/user/gopher", http.StatusMovedPermanently},
+		{http.MethodPost, "/post", false, "/post/", http.StatusPermanentRedirect},
+		{http.MethodGet, "/notfound", false, "", 0},
+	}
+	for _, tt := range tests {
+		res := router.MatchRedirect(tt.method, tt.path)
+		require.Equal(t, tt.matched, res.Matched, tt.path)
+		require.Equal(t, tt.redirect, res.Redirect, tt.path)
+		require.Equal(t, tt.code, res.RedirectCode, tt.path)
+		if !tt.matched {
+			require.Nil(t, res.Value, tt.path)
+		}
+	}
+
+	res := router.MatchRedirect(http.MethodGet, "/user/gopher")
+	require.Equal(t, "user", res.Value)
+	require.Equal(t, "/user/:name", res.Route)
+	require.Equal(t, Params{Param{"name", "gopher"}}, res.Params)
+
+	res = router.MatchRedirect(http.MethodGet, "/path/")
+	require.True(t, res.TSR)
+	res = router.MatchRedirect(http.MethodGet, "/post/")
+	require.True(t, res.MethodNotAllowed)
+
+	router = New(WithDisableRedirectTrailingSlash(), WithDisableRedirectFixedPath())
+	router.GET("/dir/", "dir")
+	res = router.MatchRedirect(http.MethodGet, "/dir")
+	require.False(t, res.Matched)
+	require.Empty(t, res.Redirect)
+	require.True(t, res.TSR)
+}
+
+func TestCaseInsensitiveParamCasing(t *testing.T) {
+	router := New()
+	router.GET("/user/:id/files/*filepath", "files")
+	router.GET("/Objects/:key", "object")
+
+	tests := []struct {
+		path     string
+		params   Params
+		redirect string
+	}{
+		{"/USER/AbC/FILES/Read.Me", Params{{"id", "AbC"}, {"filepath", "/Read.Me"}}, "/user/AbC/files/Read.Me"},
+		{"/user/AbC/Files/A/b", Params{{"id", "AbC"}, {"filepath", "/A/b"}}, "/user/AbC/files/A/b"},
+		{"//objects/./KeY-1", Params{{"key", "KeY-1"}}, "/Objects/KeY-1"},
+		{"/OBJECTS/KeY-1/", Params{{"key", "KeY-1"}}, "/Objects/KeY-1"},
+	}
+	for _, tt := range tests {
+		_, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.True(t, matched, tt.path)
+		require.Equal(t, tt.params, ps, tt.path)
+		require.Equal(t, tt.redirect, router.MatchRedirect(http.MethodGet, tt.path).Redirect, tt.path)
+	}
+}
@@ -0,0 +1,87 @@
+package wrmatch
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// CompileRegexp converts a route template, e.g. "/user/:name/*rest", into an
+// anchored regular expression matching the same request paths, for interop
+// with systems which only accept regular expressions, e.g. WAFs or log
+// processors. Named parameters match a non-empty segment satisfying their
+// constraint, except for the validity of dates, catch-alls the
+// rest of the path including the leading '/'. The wildcards are captured in
+// groups named like them, if their name is a valid group name.
+// It returns an error if the template is not a valid route path.
+func CompileRegexp(template string) (*regexp.Regexp, error) {
+	if len(template) < 1 || template[0] != '/' {
+		return nil, errors.New("path must begin with '/' in path '" + template + "'")
+	}
+	if err := registrationError(func() { new(node).addRoute(template, template) }); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for path := template; path != ""; {
+		_, i, _ := findWildcard(path)
+		if i < 0 {
+			b.WriteString(regexp.QuoteMeta(unescapePath(path)))
+			break
+		}
+		end := strings.IndexByte(path[i:], '/')
+		if end < 0 {
+			end = len(path)
+		} else {
+			end += i
+		}
+		name := path[i+1 : end]
+		if path[i] == '*' {
+			// the catch-all value includes the preceding '/', it takes at
+			// least one segment if a suffix follows
+			b.WriteString(regexp.QuoteMeta(unescapePath(path[:i-1])))
+			if end < len(path) {
+				b.WriteString(group(name, "/.+"))
+			} else {
+				b.WriteString(group(name, "/.*"))
+			}
+		} else {
+			b.WriteString(regexp.QuoteMeta(unescapePath(path[:i])))
+			name, suffix := splitSuffix(name)
+			name, constraint := splitConstraint(name)
+			expr, ok := constraintExprs[constraint]
+			if !ok {
+				expr = constraintExprs[""]
+			}
+			b.WriteString(group(name, expr))
+			b.WriteString(regexp.QuoteMeta(suffix))
+		}
+		path = path[end:]
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// constraintExprs are the expressions of the named parameters by their
+// built-in constraint, "" for the unconstrained ones and the ones with a
+// custom constraint. The dates are not validated.
+var constraintExprs = map[string]string{
+	"":      "[^/]+",
+	"int":   "-?[0-9]+",
+	"uuid":  "[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}",
+	"alpha": "[A-Za-z]+",
+	"hex":   "[0-9A-Fa-f]+",
+	"date":  "[0-9]{4}-[0-9]{2}-[0-9]{2}",
+}
+
+// groupName matches the valid names of regexp groups.
+var groupName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// group returns a capture group of expr, named name if it is a valid name.
+func group(name, expr string) string {
+	if groupName.MatchString(name) {
+		return "(?P<" + name + ">" + expr + ")"
+	}
+	return "(" + expr + ")"
+}
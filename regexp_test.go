@@ -0,0 +1,47 @@
+package wrmatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileRegexp(t *testing.T) {
+	tests := []struct {
+		template string
+		expr     string
+		matches  []string
+		misses   []string
+	}{
+		{"/", `^/$`, []string{"/"}, []string{"/a", ""}},
+		{"/a.b/c", `^/a\.b/c$`, []string{"/a.b/c"}, []string{"/axb/c", "/a.b/c/"}},
+		{"/user/:name", `^/user/(?P<name>[^/]+)$`, []string{"/user/gopher"}, []string{"/user/", "/user/a/b"}},
+		{"/user/:name/posts/:id", `^/user/(?P<name>[^/]+)/posts/(?P<id>[^/]+)$`, []string{"/user/a/posts/1"}, []string{"/user/a/posts"}},
+		{"/files/*filepath", `^/files(?P<filepath>/.*)$`, []string{"/files/", "/files/a/b"}, []string{"/files", "/filesx"}},
+		{"/user/:user-id", `^/user/([^/]+)$`, []string{"/user/1"}, nil},
+		{"/files/*path/meta.json", `^/files(?P<path>/.+)/meta\.json$`, []string{"/files/a/meta.json", "/files/a/b/meta.json"}, []string{"/files/meta.json", "/files/a/meta.jsonx"}},
+		{"/reports/:id.json", `^/reports/(?P<id>[^/]+)\.json$`, []string{"/reports/7.json"}, []string{"/reports/.json", "/reports/7.csv"}},
+		{"/user/:id<int>", `^/user/(?P<id>-?[0-9]+)$`, []string{"/user/1", "/user/-1"}, []string{"/user/a", "/user/-"}},
+	}
+	for _, tt := range tests {
+		re, err := CompileRegexp(tt.template)
+		require.NoError(t, err, tt.template)
+		require.Equal(t, tt.expr, re.String())
+		for _, path := range tt.matches {
+			require.True(t, re.MatchString(path), path)
+		}
+		for _, path := range tt.misses {
+			require.False(t, re.MatchString(path), path)
+		}
+	}
+
+	re, err := CompileRegexp("/user/:name/*rest")
+	require.NoError(t, err)
+	require.Equal(t, []string{"/user/gopher/a/b", "gopher", "/a/b"}, re.FindStringSubmatch("/user/gopher/a/b"))
+	require.Equal(t, []string{"", "name", "rest"}, re.SubexpNames())
+
+	for _, template := range []string{"", "user", "/:", "/*a/*b", "/:a:b"} {
+		_, err := CompileRegexp(template)
+		require.Error(t, err, template)
+	}
+}
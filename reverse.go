@@ -0,0 +1,41 @@
+package wrmatch
+
+// PathsFor returns the routes the value is registered under, in the same
+// stable order as Routes, e.g. to answer which URLs a handler serves.
+// Functions are compared by their code pointer, values added with
+// WithAppendValues are included. Overlays are not included.
+func (r *Router) PathsFor(value interface{}) []Route {
+	return r.PathsFunc(func(v interface{}) bool {
+		return sameValue(v, value)
+	})
+}
+
+// PathsFunc returns the routes holding a value for which match returns true,
+// in the same stable order as Routes, e.g. to compare the values by a key.
+func (r *Router) PathsFunc(match func(value interface{}) bool) []Route {
+	var routes []Route
+	for _, method := range r.methods() {
+		r.trees[method].walk("", func(path string, n *node) bool {
+			if n.hasValue(match) {
+				routes = append(routes, newRoute(method, path, n))
+			}
+			return true
+		})
+	}
+	return routes
+}
+
+// hasValue reports whether match returns true for a value of the route of n.
+func (n *node) hasValue(match func(value interface{}) bool) bool {
+	if match(unwrapValue(n.value)) {
+		return true
+	}
+	if n.meta != nil {
+		for _, v := range n.meta.values {
+			if match(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterPathsFor(t *testing.T) {
+	users := func() {}
+	router := New(WithAppendValues(), WithSaveMatchedRoutePath())
+	router.GET("/users", users)
+	router.POST("/users", users)
+	router.GET("/user/:name", "user")
+	router.GET("/user/:name", "audit")
+	router.GET("/team", "team")
+
+	routes := router.PathsFor(users)
+	require.Len(t, routes, 2)
+	require.Equal(t, http.MethodGet, routes[0].Method)
+	require.Equal(t, "/users", routes[0].Path)
+	require.Equal(t, http.MethodPost, routes[1].Method)
+
+	require.Equal(t, []Route{
+		{http.MethodGet, "/user/:name", "user", false, ""},
+	}, router.PathsFor("audit"))
+	require.Empty(t, router.PathsFor("missing"))
+
+	routes = router.PathsFunc(func(value interface{}) bool {
+		s, ok := value.(string)
+		return ok && len(s) == 4
+	})
+	require.Equal(t, []Route{
+		{http.MethodGet, "/team", "team", false, ""},
+		{http.MethodGet, "/user/:name", "user", false, ""},
+	}, routes)
+}
@@ -0,0 +1,32 @@
+package wrmatch
+
+// RouteOption overrides a router option for a single route, see Router.Add.
+type RouteOption func(*routeMeta)
+
+// StrictSlash opts the route out of the trailing slash redirect, a request
+// path with (without) a trailing slash does not match the route, even if
+// the redirect is enabled for the router.
+func StrictSlash() RouteOption {
+	return func(m *routeMeta) {
+		m.strictSlash = true
+	}
+}
+
+// NoFixedPath opts the route out of the path cleaning and the
+// case-insensitive lookup, e.g. for a webhook endpoint whose signature
+// covers the exact request path.
+func NoFixedPath() RouteOption {
+	return func(m *routeMeta) {
+		m.noFixedPath = true
+	}
+}
+
+// strictSlash reports whether the route of n is registered with StrictSlash.
+func (n *node) strictSlash() bool {
+	return n.meta != nil && n.meta.strictSlash
+}
+
+// noFixedPath reports whether the route of n is registered with NoFixedPath.
+func (n *node) noFixedPath() bool {
+	return n.meta != nil && n.meta.noFixedPath
+}
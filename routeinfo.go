@@ -0,0 +1,84 @@
+package wrmatch
+
+import (
+	"reflect"
+	"sort"
+)
+
+// RouteInfo describes the routes registered with the same path and value
+// under several methods, e.g. with Router.Handle.
+type RouteInfo struct {
+	// Methods in lexical order, MethodAny for a route added with Router.Any.
+	Methods []string
+	Path    string
+	Value   interface{}
+	// Disabled reports whether the routes were disabled with Router.Disable.
+	Disabled bool
+	// Name is the name of the route set with Router.Name, named routes are
+	// never coalesced, since names are unique.
+	Name string
+	// Params are added to the matches of the routes, see Router.RouteParams.
+	Params Params
+	// CacheHint is the cache hint set with Router.SetCacheHint, nil if there
+	// is none.
+	CacheHint *CacheHint
+}
+
+// RouteInfos returns the registered routes like Routes, but coalesces the
+// routes with the same path, value, disabled state and metadata into a single
+// RouteInfo, so that exported route tables and docs list them once.
+// The infos are ordered by path, then by their first method.
+// Overlays are not included.
+func (r *Router) RouteInfos() []RouteInfo {
+	var infos []RouteInfo
+	byPath := make(map[string][]int)
+	for _, route := range r.Routes() {
+		var params Params
+		var hint *CacheHint
+		if n := r.findRoute(route.Method, route.Path); n != nil && n.meta != nil {
+			params, hint = n.meta.routeParams(), n.meta.routeCacheHint()
+		}
+		coalesced := false
+		if route.Name == "" {
+			for _, i := range byPath[route.Path] {
+				info := &infos[i]
+				if info.Name == "" && info.Disabled == route.Disabled && sameValue(info.Value, route.Value) &&
+					reflect.DeepEqual(info.Params, params) && reflect.DeepEqual(info.CacheHint, hint) {
+					info.Methods = append(info.Methods, route.Method)
+					coalesced = true
+					break
+				}
+			}
+		}
+		if !coalesced {
+			byPath[route.Path] = append(byPath[route.Path], len(infos))
+			infos = append(infos, RouteInfo{
+				Methods:   []string{route.Method},
+				Path:      route.Path,
+				Value:     route.Value,
+				Disabled:  route.Disabled,
+				Name:      route.Name,
+				Params:    params,
+				CacheHint: hint,
+			})
+		}
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].Path < infos[j].Path
+	})
+	return infos
+}
+
+// sameValue reports whether a and b are the same value. Functions are equal
+// if they share the code pointer, other values which are not comparable,
+// e.g. slices, are never equal.
+func sameValue(a, b interface{}) bool {
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	if ta != tb {
+		return false
+	}
+	if ta.Kind() == reflect.Func {
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+	return ta.Comparable() && a == b
+}
@@ -0,0 +1,51 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterRouteInfos(t *testing.T) {
+	handler := func() {}
+	router := New()
+	router.Handle([]string{http.MethodGet, http.MethodPost, http.MethodPut}, "/form", "form")
+	router.Handle([]string{http.MethodGet, http.MethodHead}, "/func", handler)
+	router.DELETE("/form", "delete")
+	router.Any("/any", "any")
+	router.PATCH("/form", "form").Name("form.patch")
+	router.Disable(http.MethodPut, "/form")
+
+	require.Equal(t, []RouteInfo{
+		{Methods: []string{MethodAny}, Path: "/any", Value: "any"},
+		{Methods: []string{http.MethodDelete}, Path: "/form", Value: "delete"},
+		{Methods: []string{http.MethodGet, http.MethodPost}, Path: "/form", Value: "form"},
+		{Methods: []string{http.MethodPatch}, Path: "/form", Value: "form", Name: "form.patch"},
+		{Methods: []string{http.MethodPut}, Path: "/form", Value: "form", Disabled: true},
+	}, router.RouteInfos()[:5])
+	funcInfo := router.RouteInfos()[5]
+	require.Equal(t, []string{http.MethodGet, http.MethodHead}, funcInfo.Methods)
+	require.Equal(t, "/func", funcInfo.Path)
+
+	require.True(t, sameValue(handler, handler))
+	require.False(t, sameValue(handler, func() {}))
+	require.False(t, sameValue([]string{"a"}, []string{"a"}))
+	require.False(t, sameValue("1", 1))
+}
+
+func TestRouterRouteInfosMeta(t *testing.T) {
+	router := New()
+	router.Handle([]string{http.MethodGet, http.MethodPost, http.MethodPut}, "/docs/:page=1", "docs")
+	router.SetCacheHint(http.MethodGet, "/docs/:page", CacheHint{Cacheable: true})
+	router.SetCacheHint(http.MethodPost, "/docs/:page", CacheHint{Cacheable: true})
+
+	// the routes with different metadata are not coalesced
+	require.Equal(t, []RouteInfo{
+		{Methods: []string{http.MethodGet, http.MethodPost, http.MethodPut}, Path: "/docs", Value: "docs",
+			Params: Params{{"page", "1"}}},
+		{Methods: []string{http.MethodGet, http.MethodPost}, Path: "/docs/:page", Value: "docs",
+			CacheHint: &CacheHint{Cacheable: true}},
+		{Methods: []string{http.MethodPut}, Path: "/docs/:page", Value: "docs"},
+	}, router.RouteInfos())
+}
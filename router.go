@@ -73,7 +73,11 @@
 package wrmatch
 
 import (
+	"errors"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 )
 
 // MatchedRoutePathParam is the Param name under which the path of the matched
@@ -95,22 +99,44 @@ type Param struct {
 // Params is a Param-slice, as returned by the router.
 // The slice is ordered, the first URL parameter is also the first slice value.
 // It is therefore safe to read values by the index.
+//
+// The Params returned by the match functions, e.g. Router.Match, are owned by
+// the caller and may be retained and modified, except for the Params of
+// Router.MatchInto, which reuses the caller's buffer. The Params passed to the
+// callback of Pattern.MatchFunc are pooled and only valid until the callback
+// returns; use Clone to retain them.
 type Params []Param
 
 // Param returns the value of the first Param which key matches the given name.
 // If no matching Param is found, an empty string is returned.
 func (ps Params) Param(name string) string {
+	value, _ := ps.Get(name)
+	return value
+}
+
+// Get returns the value of the first Param which key matches the given name,
+// and reports whether there is one, to tell a missing Param from an empty
+// value.
+func (ps Params) Get(name string) (string, bool) {
 	for _, p := range ps {
 		if p.Key == name {
-			return p.Value
+			return p.Value, true
 		}
 	}
-	return ""
+	return "", false
+}
+
+// Has reports whether there is a Param which key matches the given name.
+func (ps Params) Has(name string) bool {
+	_, ok := ps.Get(name)
+	return ok
 }
 
 // MatchedRoutePath retrieves the path of the matched route.
 // Router.saveMatchedRoutePath must have been enabled when the respective
 // handler was added, otherwise this function always returns an empty string.
+// The path is looked up under MatchedRoutePathParam, a router with another
+// key set with WithMatchedRoutePathKey stores it under MatchedRoutePathKey.
 func (ps Params) MatchedRoutePath() string {
 	return ps.Param(MatchedRoutePathParam)
 }
@@ -122,6 +148,29 @@ type Router struct {
 	paramsNew func() *Params
 	maxParams uint16
 
+	// segments holds the first segment index per method,
+	// if firstSegmentIndex is enabled.
+	segments map[string]segmentIndex
+
+	// overlays take precedence over trees at match time,
+	// the last added one first.
+	overlays []*Router
+
+	// names maps the route names to the routes, see Router.Name.
+	names map[string]routeName
+	// last is the route added last.
+	last *routeName
+
+	// sealed is set by Router.Seal.
+	sealed bool
+
+	// routeCount and catchAllCount of the TablePolicy, they are kept by
+	// Seal for the clones.
+	routeCount    int
+	catchAllCount int
+	// memoryUsage is the estimated memory of the routes, see MemoryUsage.
+	memoryUsage int64
+
 	Options
 }
 
@@ -130,8 +179,9 @@ type Router struct {
 func New(opts ...Option) *Router {
 	r := &Router{
 		Options: Options{
-			redirectTrailingSlash: true,
-			redirectFixedPath:     true,
+			redirectTrailingSlash:   true,
+			redirectCleanPath:       true,
+			redirectCaseInsensitive: true,
 		},
 	}
 	for _, opt := range opts {
@@ -175,18 +225,61 @@ func (r *Router) DELETE(path string, value interface{}) *Router {
 	return r.Add(http.MethodDelete, path, value)
 }
 
-// Any registers a route that matches all the HTTP methods.
-// GET, POST, PUT, PATCH, HEAD, OPTIONS, DELETE, CONNECT, TRACE.
+// Any registers a route that matches all the HTTP methods of AnyMethods,
+// by default GET, POST, PUT, PATCH, HEAD, OPTIONS, DELETE, CONNECT, TRACE.
+// The route is stored once under MethodAny, routes added for a method itself
+// take precedence.
 func (r *Router) Any(path string, value interface{}) *Router {
-	return r.Add(http.MethodGet, path, value).
-		Add(http.MethodPost, path, value).
-		Add(http.MethodPut, path, value).
-		Add(http.MethodPatch, path, value).
-		Add(http.MethodHead, path, value).
-		Add(http.MethodOptions, path, value).
-		Add(http.MethodDelete, path, value).
-		Add(http.MethodConnect, path, value).
-		Add(http.MethodTrace, path, value)
+	return r.Add(MethodAny, path, value)
+}
+
+// Handle registers the value with the given path for all the given methods,
+// e.g. Handle([]string{http.MethodGet, http.MethodPost}, "/form", value).
+func (r *Router) Handle(methods []string, path string, value interface{}) *Router {
+	if len(methods) == 0 {
+		panic("methods must not be empty in path '" + path + "'")
+	}
+	for _, method := range methods {
+		r.Add(method, path, value)
+	}
+	return r
+}
+
+// StaticParam is the name of the catch-all parameter registered by Router.Static,
+// it holds the path below the prefix, including the leading '/'.
+const StaticParam = "rest"
+
+// Static registers value for the whole subtree below prefix for GET requests,
+// i.e. for the bare prefix and for every path starting with prefix + "/".
+// For example Static("/assets", v) registers "/assets" and "/assets/*rest".
+func (r *Router) Static(prefix string, value interface{}) *Router {
+	prefix = strings.TrimRight(prefix, "/")
+	if strings.ContainsAny(prefix, ":*") {
+		panic("prefix must not contain wildcards in prefix '" + prefix + "'")
+	}
+	if prefix != "" {
+		r.add(http.MethodGet, prefix, value)
+	}
+	return r.add(http.MethodGet, prefix+"/*"+StaticParam, value)
+}
+
+// Mount registers all routes of sub under the given path prefix, e.g. the
+// route "/users/:id" of sub is registered as "/api/users/:id" by
+// Mount("/api", sub). The routes keep their metadata, overlays of sub are not
+// mounted. Later changes of sub are not reflected.
+func (r *Router) Mount(prefix string, sub *Router) *Router {
+	if len(prefix) < 1 || prefix[0] != '/' {
+		panic("prefix must begin with '/' in prefix '" + prefix + "'")
+	}
+	prefix = strings.TrimRight(prefix, "/")
+	for _, method := range sub.methods() {
+		sub.trees[method].walk("", func(path string, n *node) bool {
+			r.add(method, prefix+path, unwrapValue(n.value))
+			r.copyMeta(method, prefix+path, n)
+			return true
+		})
+	}
+	return r
 }
 
 // Add registers a new request value with the given path and method.
@@ -197,8 +290,65 @@ func (r *Router) Any(path string, value interface{}) *Router {
 // This function is intended for bulk loading and to allow the usage of less
 // frequently used, non-standardized or custom methods (e.g. for internal
 // communication with a proxy).
-func (r *Router) Add(method, path string, value interface{}) *Router {
+//
+// The opts override router options for the route, e.g.
+//
+//  router.Add(http.MethodPost, "/webhook", value, wrmatch.StrictSlash(), wrmatch.NoFixedPath())
+//
+// The trailing named parameters of the path may be optional, with a default
+// value or without one, e.g.
+//
+//  router.GET("/posts/:page=1", value)
+//  router.GET("/users/:id?", value)
+//
+// registers the routes "/posts/:page" and "/posts", the latter matches with
+// the param page=1, and "/users/:id" and "/users", which matches without
+// the param id. Segments in the middle of the path can be made optional by
+// enclosing them in parentheses, e.g.
+//
+//  router.GET("/api(/v1)/users", value)
+//
+// registers the routes "/api/users" and "/api/v1/users". An optional named
+// parameter followed by other segments conflicts with them, e.g.
+// "/docs(/:version)/page" panics with a *ConflictError naming both
+// variants, before any of them is registered. The routes
+// without the optional segments share the value and the opts, they are
+// added first, so Name names the full route.
+//
+// A "*" segment which is not the last one matches a single segment, like a
+// named parameter, e.g. "/orgs/:org/*/settings" registers the route
+// "/orgs/:org/:$1/settings", the n-th of them has the param key "$n".
+// A catch-all may be followed by a fixed suffix, it then takes as many
+// segments as possible, but at least one, e.g. "/files/*path/meta.json"
+// matches "/files/a/b/meta.json" with the param path=/a/b. A "**" segment
+// is an anonymous catch-all, e.g. "/files/**/meta.json" registers the
+// route "/files/*$1/meta.json". A path has at most one catch-all.
+//
+// Named parameters may have a constraint, which their value must satisfy
+// for the route to match, e.g. "/user/:id<int>" or "/user/:id@int". The
+// constraints are int, uuid, alpha, hex and date, see checkConstraint, custom
+// constraints are registered with WithConstraint or Constraint. The routes
+// sharing a parameter must use the same constraint in the same notation.
+//
+// The rest of the segment after a named parameter from the first '.' on is
+// a literal suffix, which the segment must end with, the param value is the
+// stem, e.g. "/reports/:id.json" matches "/reports/7.json" with the param
+// id=7. The suffix follows the constraint, e.g. "/user/:id<int>.json", and
+// the routes sharing a parameter must use the same suffix.
+//
+// A literal ':' or '*' in the static part of a path is escaped with a '\',
+// e.g. `/v1/projects/\:undelete` matches "/v1/projects/:undelete". Routes
+// returns the paths with the escapes.
+func (r *Router) Add(method, path string, value interface{}, opts ...RouteOption) *Router {
+	return r.add(method, r.convertPath(path), value, opts...)
+}
+
+// add is Add for a route path, which is not converted from a net/http
+// pattern, e.g. a path of a tree.
+func (r *Router) add(method, path string, value interface{}, opts ...RouteOption) *Router {
+	r.checkSealed()
 	varsCount := uint16(0)
+	method = r.normalizeMethod(method)
 
 	if method == "" {
 		panic("method must not be empty")
@@ -209,6 +359,39 @@ func (r *Router) Add(method, path string, value interface{}) *Router {
 	if value == nil {
 		panic("value must not be nil")
 	}
+	path = nameWildcards(path)
+	r.checkConstraints(path)
+	if routes := expandRoutes(path); len(routes) > 1 || routes[0].path != path {
+		for _, route := range routes {
+			added := r.findRoute(method, route.path) == nil
+			r.add(method, route.path, value, opts...)
+			if added && len(route.params) > 0 {
+				meta := r.findRoute(method, route.path).routeMeta()
+				meta.params = append(meta.params, route.params...)
+			}
+		}
+		return r
+	}
+	if r.appendValues {
+		if n := r.findRoute(method, path); n != nil {
+			meta := n.routeMeta()
+			// the values may be shared with clones, so they are copied
+			meta.values = append(meta.values[:len(meta.values):len(meta.values)], value)
+			for _, opt := range opts {
+				opt(meta)
+			}
+			r.last = &routeName{method, path}
+			return r
+		}
+	}
+	if r.policy != nil {
+		if err := r.policy.check(method, path, r.routeCount, r.catchAllCount); err != nil {
+			panic(err)
+		}
+	}
+	if err := r.checkBudget(method, path, r.memoryUsage); err != nil {
+		panic(err)
+	}
 
 	if r.saveMatchedRoutePath {
 		value = matchValue{path, value}
@@ -226,6 +409,19 @@ func (r *Router) Add(method, path string, value interface{}) *Router {
 	}
 
 	root.addRoute(path, value)
+	meta := root.findRoute(path).routeMeta()
+	meta.route = path
+	meta.stats = r.newWildcardStats(path)
+	for _, opt := range opts {
+		opt(meta)
+	}
+	r.indexRoute(method, path)
+	r.last = &routeName{method, path}
+	r.routeCount++
+	if isCatchAll(path) {
+		r.catchAllCount++
+	}
+	r.memoryUsage += routeMemSize(path)
 
 	// Update maxParams
 	if paramsCount := countParams(path); paramsCount+varsCount > r.maxParams {
@@ -242,74 +438,954 @@ func (r *Router) Add(method, path string, value interface{}) *Router {
 	return r
 }
 
+// Update replaces the value registered with the given method and path
+// without rebuilding the tree. The path must be the registered pattern,
+// e.g. "/user/:name", not a request path, its anonymous wildcards are named
+// like by Add, e.g. "/orgs/*/settings" is "/orgs/:$1/settings". A path with
+// optional parts, e.g. "/docs(/:version)/page", stands for all its variants.
+// It reports whether a value was registered for the method and any variant
+// of the path, nothing is added otherwise.
+func (r *Router) Update(method, path string, value interface{}) bool {
+	r.checkSealed()
+	if value == nil {
+		panic("value must not be nil")
+	}
+	updated := false
+	for _, path := range routePaths(path) {
+		n := r.findRoute(method, path)
+		if n == nil {
+			continue
+		}
+		if r.saveMatchedRoutePath {
+			n.value = matchValue{path, value}
+		} else {
+			n.value = value
+		}
+		updated = true
+	}
+	return updated
+}
+
+// Disable excludes the route registered with the given method and path from
+// matching. The route stays registered, so it is still visited by Walk and
+// can be turned on again with Enable. The path must be the registered
+// pattern, not a request path, like for Update.
+// It reports whether a value was registered for the method and path.
+func (r *Router) Disable(method, path string) bool {
+	return r.setDisabled(method, path, true)
+}
+
+// Enable includes a route disabled with Disable in matching again.
+// It reports whether a value was registered for the method and path.
+func (r *Router) Enable(method, path string) bool {
+	return r.setDisabled(method, path, false)
+}
+
+// IsDisabled reports whether the route registered with the given method and
+// path was disabled with Disable, all the variants of a path with optional
+// parts.
+func (r *Router) IsDisabled(method, path string) bool {
+	paths := routePaths(path)
+	for _, path := range paths {
+		if n := r.findRoute(method, path); n == nil || n.meta == nil || !n.meta.disabled {
+			return false
+		}
+	}
+	return len(paths) > 0
+}
+
+func (r *Router) setDisabled(method, path string, disabled bool) bool {
+	r.checkSealed()
+	found := false
+	for _, path := range routePaths(path) {
+		if n := r.findRoute(method, path); n != nil {
+			n.routeMeta().disabled = disabled
+			found = true
+		}
+	}
+	return found
+}
+
+// HasRoute reports whether a value is registered with the given method and
+// path. The path must be the registered pattern, e.g. "/user/:name", it is
+// compared literally without wildcard matching, after naming the anonymous
+// wildcards like Update, all the variants of a path with optional parts must
+// be registered. Disabled routes are still
+// registered, overlays are not included. The routes added with Any are
+// registered for every method of the any methods.
+func (r *Router) HasRoute(method, path string) bool {
+	paths := routePaths(path)
+	for _, path := range paths {
+		if r.findRoute(r.routeKey(method, path), path) == nil {
+			return false
+		}
+	}
+	return len(paths) > 0
+}
+
+// routeKey returns the key of the tree holding the route registered with
+// method and path, MethodAny if only a route added with Any is registered.
+func (r *Router) routeKey(method, path string) string {
+	method = r.normalizeMethod(method)
+	if method == MethodAny || r.findRoute(method, path) != nil || !r.isAnyMethod(method) ||
+		r.findRoute(MethodAny, path) == nil {
+		return method
+	}
+	return MethodAny
+}
+
+// findRoute returns the node holding the value registered with the given
+// method and path, nil if there is none.
+func (r *Router) findRoute(method, path string) *node {
+	if root := r.trees[r.normalizeMethod(method)]; root != nil {
+		return root.findRoute(path)
+	}
+	return nil
+}
+
+// copyMeta copies the metadata of the leaf n, e.g. of another router, to the
+// route registered with the given method and path.
+func (r *Router) copyMeta(method, path string, n *node) {
+	if n.meta != nil {
+		leaf := r.findRoute(method, path)
+		meta := *n.meta
+		meta.route = path
+		meta.name = ""
+		meta.stats = leaf.routeMeta().stats
+		leaf.meta = &meta
+		if n.meta.name != "" {
+			r.setName(method, path, n.meta.name)
+		}
+	}
+}
+
+// Remove deletes the value registered with the given method and path.
+// The path must be the registered pattern, e.g. "/user/:name", not a request path,
+// like for Update, the variants of a path with optional parts are removed
+// together. The tree of the method is rebuilt without the route, so nodes which are no
+// longer needed are pruned, and the maximum number of params is recomputed.
+// It reports whether a value was registered for the method and any variant of
+// the path. A route added with Any is removed for all methods.
+//
+// Not concurrency-safe!
+func (r *Router) Remove(method, path string) bool {
+	r.checkSealed()
+	removed := false
+	for _, path := range routePaths(path) {
+		if r.remove(method, path) {
+			removed = true
+		}
+	}
+	if removed {
+		r.updateMaxParams()
+	}
+	return removed
+}
+
+// remove is Remove for a registered path, the maximum number of params is
+// not recomputed.
+func (r *Router) remove(method, path string) bool {
+	method = r.routeKey(method, path)
+	root := r.trees[method]
+	if root == nil {
+		return false
+	}
+	root, removed := root.remove(path)
+	if !removed {
+		return false
+	}
+	if root.empty() {
+		delete(r.trees, method)
+	} else {
+		r.trees[method] = root
+	}
+	r.reindex(method)
+	return true
+}
+
+// updateMaxParams recomputes maxParams, the route counts of the TablePolicy
+// and the memory usage from all registered routes.
+func (r *Router) updateMaxParams() {
+	r.maxParams = 0
+	r.routeCount, r.catchAllCount, r.memoryUsage = 0, 0, 0
+	for _, root := range r.trees {
+		root.walk("", func(path string, n *node) bool {
+			r.routeCount++
+			if isCatchAll(path) {
+				r.catchAllCount++
+			}
+			r.memoryUsage += routeMemSize(path)
+			paramsCount := countParams(path)
+			if _, ok := n.value.(matchValue); ok {
+				paramsCount++
+			}
+			if n.meta != nil {
+				paramsCount += uint16(len(n.meta.params))
+			}
+			if paramsCount > r.maxParams {
+				r.maxParams = paramsCount
+			}
+			return true
+		})
+	}
+}
+
+// Clone returns a deep copy of the router, including its overlays. The copy
+// can be modified, e.g. by adding or removing routes, while the router is
+// still serving matches, and be swapped in afterwards.
+// The values are not copied.
+func (r *Router) Clone() *Router {
+	c := &Router{
+		maxParams:     r.maxParams,
+		routeCount:    r.routeCount,
+		catchAllCount: r.catchAllCount,
+		memoryUsage:   r.memoryUsage,
+		Options:       r.Options,
+	}
+	if r.trees != nil {
+		c.trees = make(map[string]*node, len(r.trees))
+		for method, root := range r.trees {
+			c.trees[method] = root.clone()
+			c.reindex(method)
+		}
+	}
+	for _, o := range r.overlays {
+		c.overlays = append(c.overlays, o.Clone())
+	}
+	if r.names != nil {
+		c.names = make(map[string]routeName, len(r.names))
+		for name, rn := range r.names {
+			c.names[name] = rn
+		}
+	}
+	if c.maxParams > 0 {
+		c.paramsNew = func() *Params {
+			ps := make(Params, 0, c.maxParams)
+			return &ps
+		}
+	}
+	return c
+}
+
+// AllowedMethods returns the methods, in lexical order, for which a route
+// matches the given request path exactly, i.e. without trailing slash or
+// fixed path redirection. Routes of overlays are included.
+// It can be used to build the Allow header of a 405 response.
+func (r *Router) AllowedMethods(path string) []string {
+	var allowed []string
+	for _, method := range r.methods() {
+		if leaf, _, _ := r.trees[method].lookupFrom(path, 0, nil, nil, &r.Options); leaf != nil && method != MethodAny {
+			allowed = append(allowed, method)
+		}
+	}
+	if root := r.trees[MethodAny]; root != nil {
+		if leaf, _, _ := root.lookupFrom(path, 0, nil, nil, &r.Options); leaf != nil {
+			for _, method := range r.AnyMethods() {
+				allowed = insertMethod(allowed, method)
+			}
+		}
+	}
+	for _, o := range r.overlays {
+		for _, method := range o.AllowedMethods(path) {
+			allowed = insertMethod(allowed, method)
+		}
+	}
+	return allowed
+}
+
+// MethodMatch is the value and the url params matched for a single method.
+type MethodMatch struct {
+	Value  interface{}
+	Params Params
+}
+
+// MatchAllMethods looks the given request path up in the tree of every
+// method and returns the match per method. Like AllowedMethods, it only
+// considers exact matches, without trailing slash or fixed path redirection.
+// Overlays are not consulted, the routes added with Any are returned under
+// MethodAny.
+// It is useful for admin tooling and for answering OPTIONS requests.
+func (r *Router) MatchAllMethods(path string) map[string]MethodMatch {
+	matches := make(map[string]MethodMatch)
+	for method, root := range r.trees {
+		if leaf, ps, _ := root.lookupFrom(path, 0, r.paramsNew, nil, &r.Options); leaf != nil {
+			value, params := r.found(leaf, ps, nil)
+			matches[method] = MethodMatch{value, params}
+		}
+	}
+	return matches
+}
+
+// Walk calls fn for every registered route with the method, the registered
+// path and the value. The routes are visited in a stable order which does
+// not depend on the registration order: by method, then by path, both in
+// lexical order. Walk stops as soon as fn returns false.
+// Overlays are not visited.
+func (r *Router) Walk(fn func(method, path string, value interface{}) bool) {
+	for _, method := range r.methods() {
+		if !r.trees[method].walk("", func(path string, n *node) bool {
+			return fn(method, path, unwrapValue(n.value))
+		}) {
+			return
+		}
+	}
+}
+
+// Route describes a registered route.
+type Route struct {
+	Method string
+	Path   string
+	Value  interface{}
+	// Disabled reports whether the route was disabled with Router.Disable.
+	Disabled bool
+	// Name is the name of the route set with Router.Name.
+	Name string
+}
+
+// Routes returns a snapshot of all registered routes in the same stable
+// order as Walk. Overlays are not included.
+func (r *Router) Routes() []Route {
+	var routes []Route
+	for _, method := range r.methods() {
+		r.trees[method].walk("", func(path string, n *node) bool {
+			routes = append(routes, newRoute(method, path, n))
+			return true
+		})
+	}
+	return routes
+}
+
+// newRoute describes the route of the leaf n.
+func newRoute(method, path string, n *node) Route {
+	return Route{
+		Method:   method,
+		Path:     path,
+		Value:    unwrapValue(n.value),
+		Disabled: n.meta != nil && n.meta.disabled,
+		Name:     n.routeName(),
+	}
+}
+
+// unwrapValue returns the value as it was added by the user.
+func unwrapValue(value interface{}) interface{} {
+	if vv, ok := value.(matchValue); ok {
+		return vv.Value
+	}
+	return value
+}
+
+// WildcardRoute describes a registered route which contains wildcards.
+type WildcardRoute struct {
+	Method string
+	Path   string
+	// Params are the names of the named parameters in order of appearance.
+	Params []string
+	// CatchAll is the name of the catch-all parameter, empty if there is none.
+	CatchAll string
+	// Cardinality is the number of distinct values matched per wildcard
+	// name, if WithWildcardStats is enabled, nil otherwise.
+	Cardinality map[string]int
+}
+
+// WildcardReport lists every registered route which contains named or
+// catch-all parameters, ordered by method and path, with the cardinality of
+// their values, if WithWildcardStats is enabled. It helps to identify
+// templates which should be split or constrained.
+func (r *Router) WildcardReport() []WildcardRoute {
+	var report []WildcardRoute
+	for _, method := range r.methods() {
+		r.trees[method].walk("", func(path string, n *node) bool {
+			wr := WildcardRoute{Method: method, Path: path}
+			for p := path; ; {
+				wildcard, i, _ := findWildcard(p)
+				if i < 0 {
+					break
+				}
+				if wildcard[0] == '*' {
+					wr.CatchAll = wildcard[1:]
+				} else {
+					name, _ := splitSuffix(wildcard[1:])
+					name, _ = splitConstraint(name)
+					wr.Params = append(wr.Params, name)
+				}
+				p = p[i+len(wildcard):]
+			}
+			if n.meta != nil && n.meta.stats != nil {
+				wr.Cardinality = n.meta.stats.cardinality(wr.Params, wr.CatchAll)
+			}
+			if len(wr.Params) > 0 || wr.CatchAll != "" {
+				report = append(report, wr)
+			}
+			return true
+		})
+	}
+	return report
+}
+
+// methods returns the methods which have a tree in lexical order.
+func (r *Router) methods() []string {
+	methods := make([]string, 0, len(r.trees))
+	for method := range r.trees {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// WithOverlay adds an overlay router whose routes take precedence over the
+// routes of r when matching with Match, MatchURL and MatchView.
+// The trees are not merged, so the overlay can be removed again with
+// RemoveOverlay. If several overlays are added, the last added one is
+// consulted first. The routes of r and the overlays are matched exactly
+// first, the request path is only corrected if none of them matches it.
+// It panics if r is an overlay of overlay, directly or through other
+// overlays, since matching would never end.
+func (r *Router) WithOverlay(overlay *Router) *Router {
+	r.checkSealed()
+	if overlay == nil || overlay == r {
+		panic("overlay must not be nil or the router itself")
+	}
+	if overlay.hasOverlay(r) {
+		panic("overlay must not have the router as overlay")
+	}
+	r.overlays = append(r.overlays, overlay)
+	return r
+}
+
+// hasOverlay reports whether o is an overlay of r, directly or through
+// other overlays.
+func (r *Router) hasOverlay(o *Router) bool {
+	for _, overlay := range r.overlays {
+		if overlay == o || overlay.hasOverlay(o) {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveOverlay removes an overlay added with WithOverlay.
+// It reports whether the overlay was found.
+func (r *Router) RemoveOverlay(overlay *Router) bool {
+	r.checkSealed()
+	for i, o := range r.overlays {
+		if o == overlay {
+			r.overlays = append(r.overlays[:i:i], r.overlays[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AddE is like Add, but returns an error instead of panicking if the value
+// can not be registered, e.g. because of an invalid path or a conflict with
+// an already registered route. The tree of the method is restored on error.
+func (r *Router) AddE(method, path string, value interface{}, opts ...RouteOption) error {
+	return r.addE(method, func() { r.Add(method, path, value, opts...) })
+}
+
+// addE calls add, which adds a route to the tree of method, and returns the
+// panic of add as error like AddE.
+func (r *Router) addE(method string, add func()) (err error) {
+	if r.sealed {
+		return ErrSealed
+	}
+	method = r.normalizeMethod(method)
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+		switch e := rec.(type) {
+		case error:
+			err = e
+		case string:
+			err = errors.New(e)
+		default:
+			panic(rec)
+		}
+
+		// The tree may be modified partially before a conflict is
+		// detected, so it is rebuilt from the registered routes.
+		if root := r.trees[method]; root != nil {
+			if root = root.rebuild(nil); root.empty() {
+				delete(r.trees, method)
+			} else {
+				r.trees[method] = root
+			}
+			r.reindex(method)
+		}
+	}()
+
+	add()
+	return nil
+}
+
 // Lookup allows the manual lookup of a method + path combo.
 // This is e.g. useful to build a framework around this router.
 // If the path was found, it returns the value function and the path parameter
-// values. Otherwise the third return value indicates whether a redirection to
+// values, with the params of the route appended like by Match, e.g. the
+// default values of the omitted optional parameters. Otherwise the third return value indicates whether a redirection to
 // the same path with an extra / without the trailing slash should be performed.
+// The routes added with Any are looked up if the tree of the method does not
+// match, like in Match.
 func (r *Router) Lookup(method, path string) (interface{}, Params, bool) {
+	method = r.normalizeMethod(method)
+	var leaf *node
+	var ps *Params
+	var tsr bool
 	if root := r.trees[method]; root != nil {
-		value, ps, tsr := root.getValue(path, r.paramsNew)
-		if value == nil {
-			return nil, nil, tsr
-		}
-		if ps == nil {
-			return value, nil, tsr
-		}
-		return value, *ps, tsr
+		leaf, ps, tsr = root.lookupFrom(path, 0, r.paramsNew, nil, &r.Options)
+	}
+	if root := r.trees[MethodAny]; leaf == nil && root != nil && method != MethodAny && r.isAnyMethod(method) {
+		var anyTSR bool
+		leaf, ps, anyTSR = root.lookupFrom(path, 0, r.paramsNew, nil, &r.Options)
+		tsr = tsr || anyTSR
+	}
+	if leaf == nil {
+		return nil, nil, tsr
 	}
-	return nil, nil, false
+	var params Params
+	if ps != nil {
+		params = *ps
+	}
+	if leaf.meta != nil && len(leaf.meta.params) > 0 {
+		params = append(params, leaf.meta.params...)
+	}
+	return leaf.value, params, tsr
 }
 
 // Match match method and path return matched or not and store value and url params.
 func (r *Router) Match(method, path string) (interface{}, Params, bool) {
-	return r.match(method, path, r.paramsNew)
+	leaf, value, ps := r.match(method, path, r.paramsNew, nil)
+	if leaf == nil {
+		if value, ok := r.autoOptionsValue(method, path); ok {
+			return value, nil, true
+		}
+		return r.notFoundValue, nil, false
+	}
+	return value, ps, true
+}
+
+// MatchInto is like Match, but stores the url params into ps, whose storage
+// is reused, so that callers matching many paths avoid allocating the params
+// per match. ps is grown if its capacity is too small for the routes.
+// The params are overwritten by the next MatchInto with the same ps.
+func (r *Router) MatchInto(method, path string, ps *Params) (interface{}, bool) {
+	if n := r.paramsCap(); cap(*ps) < n {
+		*ps = make(Params, 0, n)
+	} else {
+		*ps = (*ps)[:0]
+	}
+	buf := *ps
+	leaf, value, params := r.match(method, path, func() *Params { return ps }, nil)
+	if params == nil {
+		*ps = buf
+	} else {
+		*ps = params
+	}
+	if leaf == nil {
+		if value, ok := r.autoOptionsValue(method, path); ok {
+			return value, true
+		}
+		return r.notFoundValue, false
+	}
+	return value, true
+}
+
+// paramsCap returns the params capacity needed by the routes of the router
+// and its overlays.
+func (r *Router) paramsCap() int {
+	n := int(r.maxParams)
+	for _, o := range r.overlays {
+		if c := o.paramsCap(); c > n {
+			n = c
+		}
+	}
+	return n
+}
+
+// ErrNotFound is returned by Router.MatchE if no route matches the path.
+var ErrNotFound = errors.New("no route matches the path")
+
+// ErrMethodNotAllowed is returned by Router.MatchE if no route matches the
+// path for the method, but for another method.
+var ErrMethodNotAllowed = errors.New("method not allowed for the path")
+
+// MatchE is like Match, but returns ErrNotFound or ErrMethodNotAllowed if
+// no route matches, so a HTTP layer can answer 404 or 405.
+func (r *Router) MatchE(method, path string) (interface{}, Params, error) {
+	leaf, value, ps := r.match(method, path, r.paramsNew, nil)
+	if leaf == nil {
+		if value, ok := r.autoOptionsValue(method, path); ok {
+			return value, nil, nil
+		}
+		if len(r.AllowedMethods(path)) > 0 {
+			return r.notFoundValue, nil, ErrMethodNotAllowed
+		}
+		return r.notFoundValue, nil, ErrNotFound
+	}
+	return value, ps, nil
 }
 
 // MatchURL match method and path return matched or not and store value and matched route path if Router.saveMatchedRoutePath enabled.
 func (r *Router) MatchURL(method, path string) (interface{}, string, bool) {
-	v, params, matched := r.match(method, path, nil)
-	return v, params.MatchedRoutePath(), matched
+	leaf, value, ps := r.match(method, path, nil, nil)
+	if leaf == nil {
+		if value, ok := r.autoOptionsValue(method, path); ok {
+			return value, "", true
+		}
+		return r.notFoundValue, "", false
+	}
+	return value, ps.Param(r.MatchedRoutePathKey()), true
 }
 
-// match match method and path return matched or not and store value and url params.
-func (r *Router) match(method, path string, paramsNew func() *Params) (interface{}, Params, bool) {
-	if root := r.trees[method]; root != nil {
-		value, ps, tsr := root.getValue(path, paramsNew)
-		if value != nil {
-			if r.saveMatchedRoutePath {
-				vv, ok := value.(matchValue)
-				if !ok {
-					panic("enabled saveMatchedRoutePath, value should be struct(matchValue)")
-				}
-				if ps == nil {
-					return vv.Value, Params{Param{MatchedRoutePathParam, vv.matchedPath}}, true
-				}
-				*ps = append(*ps, Param{MatchedRoutePathParam, vv.matchedPath})
-				return vv.Value, *ps, true
+// MatchView is like Match, but returns the url params as a ParamsView,
+// which only records offsets into the matched path instead of copying values.
+func (r *Router) MatchView(method, path string) (interface{}, ParamsView, bool) {
+	var view ParamsView
+	if r.maxParams > 0 {
+		view.spans = make([]paramSpan, 0, r.maxParams)
+	}
+	leaf, value, _ := r.match(method, path, nil, &view)
+	if leaf == nil {
+		if value, ok := r.autoOptionsValue(method, path); ok {
+			return value, ParamsView{}, true
+		}
+		return r.notFoundValue, ParamsView{}, false
+	}
+	return value, view, true
+}
+
+// MatchResult is the result of Router.MatchResult.
+type MatchResult struct {
+	// Value is the value added by the user, the value set with
+	// WithNotFoundValue if not matched.
+	Value interface{}
+	// Params are the url params, their values keep the casing of the
+	// request path, even if the path was corrected.
+	Params Params
+	// Matched reports whether a route matched.
+	Matched bool
+	// Route is the registered path of the matched route, e.g. "/user/:name".
+	Route string
+	// Name is the name of the matched route, see Router.Name.
+	Name string
+	// TSR reports whether no route matches the path exactly, but a route
+	// exists for the path with an extra (without the) trailing slash.
+	// If redirectTrailingSlash is enabled, this route is matched already.
+	TSR bool
+	// MethodNotAllowed reports whether no route matched, but a route
+	// matches the path for another method.
+	MethodNotAllowed bool
+	// Redirect is the corrected path, if no route matched the path but
+	// the path would be redirected, see Router.MatchRedirect.
+	Redirect string
+	// RedirectCode is the suggested status code of the redirect,
+	// 301 for GET requests and 308 for all other request methods.
+	RedirectCode int
+
+	meta *routeMeta
+}
+
+// MatchResult matches method and path like Match, but returns the
+// result as a MatchResult which also gives access to the matched route
+// and its metadata.
+func (r *Router) MatchResult(method, path string) MatchResult {
+	var res MatchResult
+	method = r.normalizeMethod(method)
+	if method != http.MethodConnect && path != "/" {
+		_, res.TSR = r.tsr(method, path)
+	}
+	leaf, value, ps := r.match(method, path, r.paramsNew, nil)
+	if leaf == nil {
+		if value, ok := r.autoOptionsValue(method, path); ok {
+			res.Value, res.Matched = value, true
+			return res
+		}
+		res.Value = r.notFoundValue
+		res.MethodNotAllowed = len(r.AllowedMethods(path)) > 0
+		return res
+	}
+	res.found(leaf, value, ps)
+	return res
+}
+
+// Err returns nil if a route matched, otherwise ErrMethodNotAllowed or
+// ErrNotFound like Router.MatchE.
+func (m *MatchResult) Err() error {
+	switch {
+	case m.Matched:
+		return nil
+	case m.MethodNotAllowed:
+		return ErrMethodNotAllowed
+	default:
+		return ErrNotFound
+	}
+}
+
+// found fills the result with the match of the leaf.
+func (m *MatchResult) found(leaf *node, value interface{}, ps Params) {
+	m.Value = value
+	m.Params = ps
+	m.Matched = true
+	m.meta = leaf.meta
+	if leaf.meta != nil {
+		m.Route = leaf.meta.route
+		m.Name = leaf.meta.name
+	}
+}
+
+// tsr reports whether a route matches method and path exactly, and if not,
+// whether a trailing slash redirect is recommended.
+func (r *Router) tsr(method, path string) (exact, tsr bool) {
+	for _, o := range r.overlays {
+		e, t := o.tsr(method, path)
+		if e {
+			return true, false
+		}
+		tsr = tsr || t
+	}
+	for _, key := range [2]string{method, MethodAny} {
+		if key == MethodAny && !r.isAnyMethod(method) {
+			break
+		}
+		if root := r.trees[key]; root != nil {
+			leaf, _, t := root.lookupFrom(path, 0, nil, nil, &r.Options)
+			if leaf != nil {
+				return true, false
 			}
-			if ps == nil {
-				return value, nil, true
+			tsr = tsr || t
+		}
+	}
+	return false, tsr
+}
+
+// match match method and path and returns the node holding the value,
+// the value added by the user and the url params, the node is nil if not matched.
+// If view is not nil, the offsets of the url params are recorded into it.
+func (r *Router) match(method, path string, paramsNew func() *Params, view *ParamsView) (*node, interface{}, Params) {
+	method = r.normalizeMethod(method)
+	if r.slowMatch == nil && r.onMatch == nil && r.onMiss == nil {
+		return r.matchLocale(method, path, paramsNew, view)
+	}
+	var start time.Time
+	if r.slowMatch != nil {
+		start = time.Now()
+	}
+	leaf, value, ps := r.matchLocale(method, path, paramsNew, view)
+	if r.slowMatch != nil {
+		if d := time.Since(start); d > r.slowMatchThreshold {
+			trace := r.traceMatch(method, path, d)
+			if leaf != nil {
+				trace.Route = matchedRoute(method, leaf, value)
+			}
+			r.slowMatch(trace)
+		}
+	}
+	if leaf != nil {
+		if r.onMatch != nil {
+			r.onMatch(method, path, matchedRoute(method, leaf, value))
+		}
+	} else if r.onMiss != nil {
+		r.onMiss(method, path)
+	}
+	return leaf, value, ps
+}
+
+// matchLocale is match without the slow match detection and the hooks.
+func (r *Router) matchLocale(method, path string, paramsNew func() *Params, view *ParamsView) (*node, interface{}, Params) {
+	if locale, rest, ok := r.splitLocale(path); ok {
+		if leaf, value, ps := r.matchPath(method, rest, paramsNew, view); leaf != nil {
+			if view != nil {
+				view.extra = append(view.extra, Param{LocaleParam, locale})
 			}
-			return value, *ps, true
+			return leaf, value, append(ps, Param{LocaleParam, locale})
+		}
+	}
+	return r.matchPath(method, path, paramsNew, view)
+}
+
+// matchPath is match without the locale prefix handling. The path is
+// matched exactly by the overlays and r first, it is only corrected if none
+// of them matches it.
+func (r *Router) matchPath(method, path string, paramsNew func() *Params, view *ParamsView) (*node, interface{}, Params) {
+	leaf, value, ps, spare := r.matchExact(method, path, paramsNew, view)
+	if leaf != nil {
+		return leaf, value, ps
+	}
+	if spare != nil {
+		// reuse the params of the failed lookups for the corrected path
+		paramsNew = func() *Params { return spare }
+	}
+	return r.matchCorrected(method, path, paramsNew, view)
+}
+
+// overlayParams returns the params allocator for the overlay o, the params
+// capacity must fit the overlay's routes. It is nil if paramsNew is nil.
+func overlayParams(o *Router, paramsNew func() *Params) func() *Params {
+	if paramsNew == nil {
+		return nil
+	}
+	return o.paramsNew
+}
+
+// matchExact matches path in the overlays and the trees of r without
+// correcting it. If none matches, it returns the emptied params allocated
+// by the failed lookups in the trees of r, if any.
+func (r *Router) matchExact(method, path string, paramsNew func() *Params, view *ParamsView) (*node, interface{}, Params, *Params) {
+	for i := len(r.overlays) - 1; i >= 0; i-- {
+		o := r.overlays[i]
+		if leaf, value, ps, _ := o.matchExact(method, path, overlayParams(o, paramsNew), view); leaf != nil {
+			return leaf, value, ps, nil
+		}
+	}
+	leaf, value, ps, spare := r.matchTree(method, path, paramsNew, view)
+	if leaf != nil {
+		return leaf, value, ps, nil
+	}
+	if method != MethodAny && r.trees[MethodAny] != nil && r.isAnyMethod(method) {
+		if spare != nil {
+			paramsNew = func() *Params { return spare }
 		}
+		var anySpare *Params
+		if leaf, value, ps, anySpare = r.matchTree(MethodAny, path, paramsNew, view); leaf != nil {
+			return leaf, value, ps, nil
+		}
+		if anySpare != nil {
+			spare = anySpare
+		}
+	}
+	return nil, nil, nil, spare
+}
+
+// matchCorrected matches the corrected path in the overlays and the trees
+// of r, the corrections of the overlays are tried first.
+func (r *Router) matchCorrected(method, path string, paramsNew func() *Params, view *ParamsView) (*node, interface{}, Params) {
+	for i := len(r.overlays) - 1; i >= 0; i-- {
+		o := r.overlays[i]
+		if leaf, value, ps := o.matchCorrected(method, path, overlayParams(o, paramsNew), view); leaf != nil {
+			return leaf, value, ps
+		}
+	}
+	if leaf, value, ps := r.correctTree(method, method, path, paramsNew, view); leaf != nil {
+		return leaf, value, ps
+	}
+	if method != MethodAny && r.trees[MethodAny] != nil && r.isAnyMethod(method) {
+		return r.correctTree(MethodAny, method, path, paramsNew, view)
+	}
+	return nil, nil, nil
+}
+
+// matchTree matches path exactly in the tree stored under key. If it does
+// not match, it returns the emptied params of the failed lookup, if any.
+func (r *Router) matchTree(key, path string, paramsNew func() *Params, view *ParamsView) (*node, interface{}, Params, *Params) {
+	if root := r.trees[key]; root != nil {
+		if view != nil {
+			view.path = path
+			view.spans = view.spans[:0]
+		}
+		leaf, ps, _ := r.lookup(key, root, path, paramsNew, view)
+		if leaf != nil {
+			value, params := r.found(leaf, ps, view)
+			return leaf, value, params, nil
+		}
+		if ps != nil {
+			*ps = (*ps)[:0]
+		}
+		return nil, nil, nil, ps
+	}
+	return nil, nil, nil, nil
+}
+
+// correctTree matches the path corrected by the tree stored under key for
+// method, i.e. with (without) a trailing slash or the fixed path. The
+// corrected path is matched exactly, it is never corrected again.
+func (r *Router) correctTree(key, method, path string, paramsNew func() *Params, view *ParamsView) (*node, interface{}, Params) {
+	if root := r.trees[key]; root != nil {
 		if method != http.MethodConnect && path != "/" {
-			if tsr && r.redirectTrailingSlash {
+			_, _, tsr := r.lookup(key, root, path, nil, nil)
+			opts := r.pathOptions(path)
+			if tsr && opts.redirectTrailingSlash {
 				if len(path) > 1 && path[len(path)-1] == '/' {
 					path = path[:len(path)-1]
 				} else {
 					path += "/"
 				}
-				return r.Match(method, path)
+				if leaf, value, ps, _ := r.matchExact(method, path, paramsNew, view); leaf != nil && !leaf.strictSlash() {
+					return leaf, value, ps
+				}
+				return nil, nil, nil
 			}
 			// Try to fix the request path
-			if r.redirectFixedPath {
-				fixedPath, found := root.findCaseInsensitivePath(CleanPath(path), r.redirectTrailingSlash)
-				if found {
-					return r.Match(method, fixedPath)
+			if fixedPath, found := opts.fixPath(root, path); found && fixedPath != path {
+				if leaf, value, ps, _ := r.matchExact(method, fixedPath, paramsNew, view); leaf != nil && !leaf.noFixedPath() {
+					return leaf, value, ps
 				}
 			}
 		}
 	}
-	return nil, nil, false
+	return nil, nil, nil
+}
+
+// found returns the value added by the user and the url params of a match
+// of the leaf, the matched route path is appended if Router.saveMatchedRoutePath
+// is enabled. The value is passed through the value resolver, if set.
+func (r *Router) found(leaf *node, ps *Params, view *ParamsView) (interface{}, Params) {
+	value := leaf.value
+	var params Params
+	if ps != nil {
+		r.fixParams(*ps)
+	}
+	if leaf.meta != nil && leaf.meta.stats != nil {
+		leaf.meta.stats.record(ps, view)
+	}
+	if r.saveMatchedRoutePath {
+		vv, ok := value.(matchValue)
+		if !ok {
+			panic("enabled saveMatchedRoutePath, value should be struct(matchValue)")
+		}
+		if view != nil {
+			view.matchedPath = vv.matchedPath
+			view.matchedKey = r.MatchedRoutePathKey()
+		}
+		value = vv.Value
+		if ps == nil {
+			params = Params{Param{r.MatchedRoutePathKey(), vv.matchedPath}}
+		} else {
+			*ps = append(*ps, Param{r.MatchedRoutePathKey(), vv.matchedPath})
+			params = *ps
+		}
+	} else if ps != nil {
+		params = *ps
+	}
+	if leaf.meta != nil && len(leaf.meta.params) > 0 {
+		params = append(params, leaf.meta.params...)
+		if view != nil {
+			view.extra = append(view.extra, leaf.meta.params...)
+		}
+	}
+	if r.valueResolver != nil {
+		value = r.resolveValue(leaf, value, params)
+	}
+	return value, params
+}
+
+// fixParams trims and unescapes the values of the params of a lookup in
+// place, if enabled.
+func (o *Options) fixParams(ps Params) {
+	if !o.trimCatchAllSlash && !o.unescapeParams {
+		return
+	}
+	for i := range ps {
+		value := ps[i].Value
+		// only catch-all values start with a slash
+		catchAll := len(value) > 0 && value[0] == '/'
+		if catchAll && o.trimCatchAllSlash {
+			value = value[1:]
+		}
+		if o.unescapeParams {
+			value = unescapeParam(value, catchAll)
+		}
+		ps[i].Value = value
+	}
 }
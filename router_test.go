@@ -5,8 +5,11 @@
 package wrmatch
 
 import (
+	"math/rand"
 	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -138,6 +141,26 @@ func TestRouterMatchRedirectTrailingSlash(t *testing.T) {
 	require.True(t, matched)
 }
 
+func TestRouterCorrectOnce(t *testing.T) {
+	var traces []MatchTrace
+	router := New(WithSlowMatch(-1, func(trace MatchTrace) {
+		traces = append(traces, trace)
+	}))
+	router.GET("/files/:dir/:name", "file")
+	router.Any("/files/:dir/**/meta.json", "meta")
+
+	// the corrected path is not corrected again
+	_, _, matched := router.Match(http.MethodGet, "/files/x")
+	require.False(t, matched)
+	require.Len(t, traces, 1)
+	require.False(t, router.MatchRedirect(http.MethodGet, "/files/x").Matched)
+	require.Equal(t, "", router.MatchRedirect(http.MethodGet, "/files/x").Redirect)
+
+	value, _, matched := router.Match(http.MethodGet, "/files/x/y/")
+	require.True(t, matched)
+	require.Equal(t, "file", value)
+}
+
 func TestRouterRedirect(t *testing.T) {
 	router := New()
 	router.GET("/path", "/path")
@@ -168,6 +191,36 @@ func TestRouterRedirect(t *testing.T) {
 	}
 }
 
+func TestRouterRedirectCleanPathOnly(t *testing.T) {
+	router := New(WithDisableRedirectCaseInsensitive())
+	router.GET("/path", "/path")
+	router.GET("/dir/", "/dir/")
+	router.GET("/Key/:id", "/Key/:id")
+
+	tests := []struct {
+		path    string
+		matched bool
+	}{
+		{"/../path", true},
+		{"/dir/..//dir", true},
+		{"/Key/./abc", true},
+		{"/PATH", false},
+		{"/key/abc", false},
+		{"/../PATH", false},
+	}
+	for _, tt := range tests {
+		_, _, matched := router.Match(http.MethodGet, tt.path)
+		assert.Equal(t, tt.matched, matched, tt.path)
+	}
+
+	router = New(WithDisableRedirectCleanPath())
+	router.GET("/path", "/path")
+	_, _, matched := router.Match(http.MethodGet, "/PATH")
+	assert.True(t, matched)
+	_, _, matched = router.Match(http.MethodGet, "/../path")
+	assert.False(t, matched)
+}
+
 func TestRouterDisableRedirect(t *testing.T) {
 	router := New(WithDisableRedirectFixedPath(), WithDisableRedirectTrailingSlash())
 	router.GET("/path", "/path")
@@ -304,3 +357,1132 @@ func BenchmarkMatchURL(b *testing.B) {
 		router.MatchURL(http.MethodGet, "/GET/myName")
 	}
 }
+
+func BenchmarkMatchFixedPath(b *testing.B) {
+	router := New()
+	router.GET("/user/:name/files", "files")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		router.Match(http.MethodGet, "/USER/gopher//files")
+	}
+}
+
+func BenchmarkMatchTrailingSlash(b *testing.B) {
+	router := New()
+	router.GET("/user/:name/files", "files")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		router.Match(http.MethodGet, "/user/gopher/files/")
+	}
+}
+
+func BenchmarkMatchInto(b *testing.B) {
+	router := New()
+	router.GET("/GET/:name", "get")
+	var ps Params
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		router.MatchInto(http.MethodGet, "/GET/myName", &ps)
+	}
+}
+
+func TestRouterMatchInto(t *testing.T) {
+	router := New()
+	router.GET("/user/:name/files/*filepath", "files")
+	router.GET("/static", "static")
+
+	var ps Params
+	v, matched := router.MatchInto(http.MethodGet, "/user/gopher/files/a.txt", &ps)
+	require.True(t, matched)
+	require.Equal(t, "files", v)
+	require.Equal(t, Params{Param{"name", "gopher"}, Param{"filepath", "/a.txt"}}, ps)
+
+	buf := ps
+	v, matched = router.MatchInto(http.MethodGet, "/static/", &ps)
+	require.True(t, matched)
+	require.Equal(t, "static", v)
+	require.Empty(t, ps)
+	require.Equal(t, cap(buf), cap(ps))
+
+	_, matched = router.MatchInto(http.MethodGet, "/USER/gopher//files/b.txt", &ps)
+	require.True(t, matched)
+	require.Equal(t, Params{Param{"name", "gopher"}, Param{"filepath", "/b.txt"}}, ps)
+	require.Same(t, &buf[0], &ps[0])
+
+	_, matched = router.MatchInto(http.MethodGet, "/unknown", &ps)
+	require.False(t, matched)
+	require.Empty(t, ps)
+
+	// the params are grown for the routes of the overlays
+	overlay := New()
+	overlay.GET("/a/:b/:c/:d", "overlay")
+	router.WithOverlay(overlay)
+	ps = make(Params, 0, 1)
+	_, matched = router.MatchInto(http.MethodGet, "/a/1/2/3", &ps)
+	require.True(t, matched)
+	require.Len(t, ps, 3)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		router.MatchInto(http.MethodGet, "/user/gopher/files/a.txt", &ps)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestRouterMatchView(t *testing.T) {
+	router := New()
+	router.GET("/user/:name/files/*filepath", "files")
+	router.GET("/static", "static")
+
+	v, view, matched := router.MatchView(http.MethodGet, "/user/gopher/files/a/b.txt")
+	require.True(t, matched)
+	require.Equal(t, "files", v)
+	require.Equal(t, 2, view.Len())
+	require.Equal(t, "name", view.Key(0))
+	require.Equal(t, "gopher", view.Value(0))
+	require.Equal(t, "/a/b.txt", view.Param("filepath"))
+	require.Empty(t, view.Param("noKey"))
+	require.Equal(t, Params{Param{"name", "gopher"}, Param{"filepath", "/a/b.txt"}}, view.Params())
+
+	v, view, matched = router.MatchView(http.MethodGet, "/static/")
+	require.True(t, matched)
+	require.Equal(t, "static", v)
+	require.Equal(t, 0, view.Len())
+	require.Nil(t, view.Params())
+
+	v, view, matched = router.MatchView(http.MethodGet, "/notfound")
+	require.False(t, matched)
+	require.Nil(t, v)
+	require.Equal(t, 0, view.Len())
+}
+
+func TestRouterMatchViewEnableSaveMatchedRouterPath(t *testing.T) {
+	router := New(WithSaveMatchedRoutePath())
+	router.GET("/user/:name", "user")
+
+	v, view, matched := router.MatchView(http.MethodGet, "/USER/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, "/user/:name", view.MatchedRoutePath())
+	require.Equal(t, Params{Param{"name", "gopher"}, {MatchedRoutePathParam, "/user/:name"}}, view.Params())
+}
+
+func TestRouterOverlay(t *testing.T) {
+	base := New()
+	base.GET("/user/:name", "base-user")
+	base.GET("/about", "base-about")
+
+	overlay := New()
+	overlay.GET("/user/:name", "overlay-user")
+	overlay.GET("/canary/:id/:sub", "overlay-canary")
+
+	require.Panics(t, func() {
+		base.WithOverlay(base)
+	})
+	base.WithOverlay(overlay)
+
+	v, ps, matched := base.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "overlay-user", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+
+	v, ps, matched = base.Match(http.MethodGet, "/canary/1/2")
+	require.True(t, matched)
+	require.Equal(t, "overlay-canary", v)
+	require.Equal(t, Params{Param{"id", "1"}, Param{"sub", "2"}}, ps)
+
+	v, _, matched = base.MatchURL(http.MethodGet, "/about")
+	require.True(t, matched)
+	require.Equal(t, "base-about", v)
+
+	require.True(t, base.RemoveOverlay(overlay))
+	require.False(t, base.RemoveOverlay(overlay))
+
+	v, _, matched = base.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "base-user", v)
+
+	_, _, matched = base.Match(http.MethodGet, "/canary/1/2")
+	require.False(t, matched)
+}
+
+func TestRouterOverlayExactFirst(t *testing.T) {
+	base := New()
+	base.GET("/about", "base-about")
+	base.GET("/USERS", "base-users")
+	overlay := New()
+	overlay.GET("/about/", "overlay-about")
+	overlay.GET("/users", "overlay-users")
+	base.WithOverlay(overlay)
+
+	// the exact route of the base wins over the corrected one of the overlay
+	v, _, matched := base.Match(http.MethodGet, "/about")
+	require.True(t, matched)
+	require.Equal(t, "base-about", v)
+	res := base.MatchResult(http.MethodGet, "/about")
+	require.Equal(t, "/about", res.Route)
+	require.False(t, res.TSR)
+	require.Empty(t, base.MatchRedirect(http.MethodGet, "/about").Redirect)
+
+	v, _, matched = base.Match(http.MethodGet, "/USERS")
+	require.True(t, matched)
+	require.Equal(t, "base-users", v)
+
+	// the overlay is still corrected first
+	v, _, matched = base.Match(http.MethodGet, "/about//")
+	require.True(t, matched)
+	require.Equal(t, "overlay-about", v)
+
+	trace := base.traceMatch(http.MethodGet, "/about", 0)
+	require.Len(t, trace.Steps, 2)
+	require.True(t, trace.Steps[1].Matched)
+}
+
+func TestRouterOverlayCycle(t *testing.T) {
+	a, b, c := New(), New(), New()
+	a.WithOverlay(b)
+	b.WithOverlay(c)
+	require.Panics(t, func() { b.WithOverlay(a) })
+	require.Panics(t, func() { c.WithOverlay(a) })
+	require.NotPanics(t, func() { a.WithOverlay(c) })
+
+	_, _, matched := a.Match(http.MethodGet, "/missing")
+	require.False(t, matched)
+}
+
+func TestRouterRemove(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/user/:name/files/*filepath", "files")
+	router.GET("/about", "about")
+	router.POST("/about", "post-about")
+	require.Equal(t, uint16(2), router.maxParams)
+
+	require.False(t, router.Remove(http.MethodGet, "/user/gopher"))
+	require.False(t, router.Remove(http.MethodPut, "/about"))
+
+	require.True(t, router.Remove(http.MethodGet, "/user/:name/files/*filepath"))
+	require.False(t, router.Remove(http.MethodGet, "/user/:name/files/*filepath"))
+	require.Equal(t, uint16(1), router.maxParams)
+
+	_, _, matched := router.Match(http.MethodGet, "/user/gopher/files/a.txt")
+	require.False(t, matched)
+	v, ps, matched := router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+
+	// the removed path can be registered again
+	router.GET("/user/:name/files/*filepath", "files2")
+	v, _, matched = router.Match(http.MethodGet, "/user/gopher/files/a.txt")
+	require.True(t, matched)
+	require.Equal(t, "files2", v)
+
+	require.True(t, router.Remove(http.MethodPost, "/about"))
+	require.Nil(t, router.trees[http.MethodPost])
+	v, _, matched = router.Match(http.MethodGet, "/about")
+	require.True(t, matched)
+	require.Equal(t, "about", v)
+}
+
+func TestRouterWildcardReport(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/about", "about")
+	router.GET("/user/:name/files/*filepath", "files")
+	router.DELETE("/src/*filepath", "src")
+
+	require.Equal(t, []WildcardRoute{
+		{Method: http.MethodDelete, Path: "/src/*filepath", CatchAll: "filepath"},
+		{Method: http.MethodGet, Path: "/user/:name", Params: []string{"name"}},
+		{Method: http.MethodGet, Path: "/user/:name/files/*filepath", Params: []string{"name"}, CatchAll: "filepath"},
+	}, router.WildcardReport())
+
+	require.Empty(t, New().WildcardReport())
+}
+
+func TestRouterUpdate(t *testing.T) {
+	router := New(WithSaveMatchedRoutePath())
+	router.GET("/user/:name", "user")
+	router.GET("/src/*filepath", "src")
+
+	require.Panics(t, func() {
+		router.Update(http.MethodGet, "/user/:name", nil)
+	})
+	require.False(t, router.Update(http.MethodGet, "/user/gopher", "x"))
+	require.False(t, router.Update(http.MethodGet, "/user", "x"))
+	require.False(t, router.Update(http.MethodPost, "/user/:name", "x"))
+
+	require.True(t, router.Update(http.MethodGet, "/user/:name", "user2"))
+	require.True(t, router.Update(http.MethodGet, "/src/*filepath", "src2"))
+
+	v, ps, matched := router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user2", v)
+	require.Equal(t, "/user/:name", ps.MatchedRoutePath())
+
+	v, _, matched = router.Match(http.MethodGet, "/src/a/b")
+	require.True(t, matched)
+	require.Equal(t, "src2", v)
+}
+
+func TestRouterWalk(t *testing.T) {
+	type route struct {
+		method, path string
+		value        interface{}
+	}
+	want := []route{
+		{http.MethodDelete, "/user/:name", "delete"},
+		{http.MethodGet, "/", "root"},
+		{http.MethodGet, "/about", "about"},
+		{http.MethodGet, "/src/*filepath", "src"},
+		{http.MethodGet, "/user/:name", "user"},
+		{http.MethodGet, "/user/:name/about", "user-about"},
+		{http.MethodPost, "/user", "post"},
+	}
+
+	// registration order must not affect the walk order
+	for _, order := range [][]int{{0, 1, 2, 3, 4, 5, 6}, {6, 5, 4, 3, 2, 1, 0}, {3, 0, 5, 1, 6, 2, 4}} {
+		router := New(WithSaveMatchedRoutePath())
+		for _, i := range order {
+			router.Add(want[i].method, want[i].path, want[i].value)
+		}
+
+		var got []route
+		router.Walk(func(method, path string, value interface{}) bool {
+			got = append(got, route{method, path, value})
+			return true
+		})
+		require.Equal(t, want, got)
+	}
+
+	router := New()
+	for _, rt := range want {
+		router.Add(rt.method, rt.path, rt.value)
+	}
+	count := 0
+	router.Walk(func(method, path string, value interface{}) bool {
+		count++
+		return count < 3
+	})
+	require.Equal(t, 3, count)
+}
+
+func TestRouterOrderIndependence(t *testing.T) {
+	var table []Route
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete, MethodAny} {
+		for _, path := range []string{
+			"/", "/a", "/ab", "/abc", "/b/", "/b/:id<int>", "/b/:id<int>/c", "/b/:id<int>/d.json",
+			"/c/:name.json", "/d/*path", "/e/:x/:y", "/e/:x/:y/z", "/f/*path/meta.json", "/g/\\:h",
+			"/user_:name", "/user_:name/about", "/z", "/zz/", "/Z",
+		} {
+			table = append(table, Route{Method: method, Path: path, Value: method + " " + path})
+		}
+	}
+	for i := 0; i < 50; i++ {
+		path := "/gen/" + strconv.Itoa(i*7%50) + "/:id"
+		table = append(table, Route{Method: http.MethodPut, Path: path, Value: path})
+	}
+
+	collect := func(router *Router) ([]Route, []Route) {
+		var walked []Route
+		router.Walk(func(method, path string, value interface{}) bool {
+			walked = append(walked, Route{Method: method, Path: path, Value: value})
+			return true
+		})
+		return router.Routes(), walked
+	}
+
+	var wantRoutes, wantWalked []Route
+	for seed := int64(0); seed < 20; seed++ {
+		routes := append([]Route(nil), table...)
+		rand.New(rand.NewSource(seed)).Shuffle(len(routes), func(i, j int) {
+			routes[i], routes[j] = routes[j], routes[i]
+		})
+		router := New(WithSaveMatchedRoutePath())
+		for _, route := range routes {
+			router.Add(route.Method, route.Path, route.Value)
+		}
+		// matches change the priorities, which must not affect the order
+		for _, route := range routes[:10] {
+			router.Match(route.Method, route.Path)
+		}
+
+		gotRoutes, gotWalked := collect(router)
+		require.Len(t, gotRoutes, len(table))
+		require.True(t, sort.SliceIsSorted(gotRoutes, func(i, j int) bool {
+			return gotRoutes[i].Method < gotRoutes[j].Method ||
+				gotRoutes[i].Method == gotRoutes[j].Method && gotRoutes[i].Path < gotRoutes[j].Path
+		}))
+		if seed == 0 {
+			wantRoutes, wantWalked = gotRoutes, gotWalked
+			continue
+		}
+		require.Equal(t, wantRoutes, gotRoutes, "seed %d", seed)
+		require.Equal(t, wantWalked, gotWalked, "seed %d", seed)
+
+		// removing and re-adding a route keeps the order
+		route := routes[seed]
+		require.True(t, router.Remove(route.Method, route.Path))
+		router.Add(route.Method, route.Path, route.Value)
+		gotRoutes, gotWalked = collect(router)
+		require.Equal(t, wantRoutes, gotRoutes, "seed %d", seed)
+		require.Equal(t, wantWalked, gotWalked, "seed %d", seed)
+	}
+}
+
+func TestRouterDisable(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/path", "path")
+	router.GET("/src/*filepath", "src")
+
+	require.False(t, router.Disable(http.MethodGet, "/user/gopher"))
+	require.False(t, router.Disable(http.MethodPost, "/path"))
+
+	require.True(t, router.Disable(http.MethodGet, "/user/:name"))
+	require.True(t, router.Disable(http.MethodGet, "/path"))
+	require.True(t, router.Disable(http.MethodGet, "/src/*filepath"))
+	require.True(t, router.IsDisabled(http.MethodGet, "/path"))
+
+	for _, path := range []string{"/user/gopher", "/path", "/path/", "/PATH", "/src/a"} {
+		_, _, matched := router.Match(http.MethodGet, path)
+		require.False(t, matched, path)
+	}
+
+	// disabled routes are still registered
+	require.Panics(t, func() {
+		router.GET("/path", "path")
+	})
+	count := 0
+	router.Walk(func(method, path string, value interface{}) bool {
+		count++
+		return true
+	})
+	require.Equal(t, 3, count)
+
+	// and survive rebuilding the tree
+	router.GET("/other", "other")
+	require.True(t, router.Remove(http.MethodGet, "/other"))
+	require.True(t, router.IsDisabled(http.MethodGet, "/path"))
+
+	require.True(t, router.Enable(http.MethodGet, "/path"))
+	require.False(t, router.IsDisabled(http.MethodGet, "/path"))
+	v, _, matched := router.Match(http.MethodGet, "/PATH/")
+	require.True(t, matched)
+	require.Equal(t, "path", v)
+}
+
+func TestRouterRoutes(t *testing.T) {
+	router := New(WithSaveMatchedRoutePath())
+	require.Empty(t, router.Routes())
+
+	router.POST("/user", "post")
+	router.GET("/user/:name", "user")
+	router.GET("/", "root")
+	router.Disable(http.MethodGet, "/")
+
+	require.Equal(t, []Route{
+		{Method: http.MethodGet, Path: "/", Value: "root", Disabled: true},
+		{Method: http.MethodGet, Path: "/user/:name", Value: "user"},
+		{Method: http.MethodPost, Path: "/user", Value: "post"},
+	}, router.Routes())
+}
+
+func TestRouterAllowedMethods(t *testing.T) {
+	router := New()
+	router.GET("/path", "get")
+	router.POST("/path", "post")
+	router.DELETE("/user/:name", "delete")
+	router.PUT("/user/:name", "put")
+	router.PUT("/dir/", "put")
+
+	overlay := New()
+	overlay.PATCH("/path", "patch")
+	overlay.GET("/path", "get")
+	router.WithOverlay(overlay)
+
+	require.Equal(t, []string{http.MethodGet, http.MethodPatch, http.MethodPost}, router.AllowedMethods("/path"))
+	require.Equal(t, []string{http.MethodDelete, http.MethodPut}, router.AllowedMethods("/user/gopher"))
+	require.Empty(t, router.AllowedMethods("/dir"))
+	require.Empty(t, router.AllowedMethods("/notfound"))
+}
+
+func TestRouterMatchAllMethods(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "get")
+	router.DELETE("/user/:id", "delete")
+	router.POST("/user/", "post")
+
+	require.Equal(t, map[string]MethodMatch{
+		http.MethodGet:    {"get", Params{Param{"name", "gopher"}}},
+		http.MethodDelete: {"delete", Params{Param{"id", "gopher"}}},
+	}, router.MatchAllMethods("/user/gopher"))
+	require.Equal(t, map[string]MethodMatch{
+		http.MethodPost: {"post", nil},
+	}, router.MatchAllMethods("/user/"))
+	require.Empty(t, router.MatchAllMethods("/user"))
+
+	router = New(WithSaveMatchedRoutePath())
+	router.GET("/user/:name", "get")
+	require.Equal(t, map[string]MethodMatch{
+		http.MethodGet: {"get", Params{Param{"name", "gopher"}, Param{MatchedRoutePathParam, "/user/:name"}}},
+	}, router.MatchAllMethods("/user/gopher"))
+}
+
+func TestRouterStatic(t *testing.T) {
+	router := New()
+	router.Static("/assets/", "assets")
+	router.GET("/assetsx", "assetsx")
+
+	tests := []struct {
+		path  string
+		value interface{}
+		rest  string
+	}{
+		{"/assets", "assets", ""},
+		{"/assets/", "assets", "/"},
+		{"/assets/css/site.css", "assets", "/css/site.css"},
+		{"/assetsx", "assetsx", ""},
+	}
+	for _, tt := range tests {
+		v, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.True(t, matched, tt.path)
+		require.Equal(t, tt.value, v, tt.path)
+		require.Equal(t, tt.rest, ps.Param(StaticParam), tt.path)
+	}
+
+	router = New()
+	router.Static("/", "root")
+	v, ps, matched := router.Match(http.MethodGet, "/a/b")
+	require.True(t, matched)
+	require.Equal(t, "root", v)
+	require.Equal(t, "/a/b", ps.Param(StaticParam))
+
+	require.Panics(t, func() { New().Static("/:dir", "dir") })
+}
+
+func TestRouterMatchResult(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/dir/", "dir")
+	router.POST("/post", "post")
+
+	res := router.MatchResult(http.MethodGet, "/user/gopher")
+	require.True(t, res.Matched)
+	require.Equal(t, "user", res.Value)
+	require.Equal(t, Params{Param{"name", "gopher"}}, res.Params)
+	require.Equal(t, "/user/:name", res.Route)
+	require.False(t, res.TSR)
+	require.False(t, res.MethodNotAllowed)
+
+	res = router.MatchResult(http.MethodGet, "/dir")
+	require.True(t, res.Matched)
+	require.Equal(t, "/dir/", res.Route)
+	require.True(t, res.TSR)
+
+	res = router.MatchResult(http.MethodGet, "/post")
+	require.False(t, res.Matched)
+	require.Nil(t, res.Value)
+	require.Empty(t, res.Route)
+	require.True(t, res.MethodNotAllowed)
+
+	res = router.MatchResult(http.MethodGet, "/notfound")
+	require.False(t, res.Matched)
+	require.False(t, res.MethodNotAllowed)
+
+	router = New(WithDisableRedirectTrailingSlash())
+	router.GET("/dir/", "dir")
+	res = router.MatchResult(http.MethodGet, "/dir")
+	require.False(t, res.Matched)
+	require.True(t, res.TSR)
+}
+
+func TestRouterHasRoute(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/src/*filepath", "src")
+	router.GET("/users", "users")
+
+	require.True(t, router.HasRoute(http.MethodGet, "/user/:name"))
+	require.True(t, router.HasRoute(http.MethodGet, "/src/*filepath"))
+	require.True(t, router.HasRoute(http.MethodGet, "/users"))
+	require.False(t, router.HasRoute(http.MethodGet, "/user/gopher"))
+	require.False(t, router.HasRoute(http.MethodGet, "/user/:id"))
+	require.False(t, router.HasRoute(http.MethodGet, "/user"))
+	require.False(t, router.HasRoute(http.MethodPost, "/users"))
+
+	router.Disable(http.MethodGet, "/users")
+	require.True(t, router.HasRoute(http.MethodGet, "/users"))
+	router.Remove(http.MethodGet, "/users")
+	require.False(t, router.HasRoute(http.MethodGet, "/users"))
+}
+
+func TestRouterValueResolver(t *testing.T) {
+	backends := map[int]string{1: "user-backend", 2: "team-backend"}
+	var routes []string
+	var names []string
+	router := New(WithValueResolver(func(stored interface{}, res *MatchResult) interface{} {
+		routes = append(routes, res.Route)
+		names = append(names, res.Name)
+		return backends[stored.(int)]
+	}))
+	router.GET("/user/:name", 1).Name("user")
+	router.GET("/team", 2)
+
+	v, ps, matched := router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user-backend", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+
+	res := router.MatchResult(http.MethodGet, "/team/")
+	require.True(t, res.Matched)
+	require.Equal(t, "team-backend", res.Value)
+
+	backends[2] = "new-team-backend"
+	v, _, _ = router.Match(http.MethodGet, "/team")
+	require.Equal(t, "new-team-backend", v)
+
+	_, _, matched = router.Match(http.MethodGet, "/notfound")
+	require.False(t, matched)
+	require.Equal(t, []string{"/user/:name", "/team", "/team"}, routes)
+	require.Equal(t, []string{"user", "", ""}, names)
+}
+
+func TestRouterClone(t *testing.T) {
+	router := New(WithFirstSegmentIndex())
+	router.GET("/user/:name", "user")
+	router.GET("/users", "users")
+	router.GET("/team", "team")
+	router.Disable(http.MethodGet, "/team")
+
+	clone := router.Clone()
+	require.Equal(t, router.Routes(), clone.Routes())
+
+	clone.GET("/user/:name/:id/:tab", "tab")
+	clone.Remove(http.MethodGet, "/users")
+	clone.Enable(http.MethodGet, "/team")
+	checkPriorities(t, clone.trees[http.MethodGet])
+
+	v, ps, matched := clone.Match(http.MethodGet, "/user/gopher/1/about")
+	require.True(t, matched)
+	require.Equal(t, "tab", v)
+	require.Len(t, ps, 3)
+	_, _, matched = clone.Match(http.MethodGet, "/team")
+	require.True(t, matched)
+
+	// the router is unchanged
+	require.Equal(t, []Route{
+		{http.MethodGet, "/team", "team", true, ""},
+		{http.MethodGet, "/user/:name", "user", false, ""},
+		{http.MethodGet, "/users", "users", false, ""},
+	}, router.Routes())
+	_, _, matched = router.Match(http.MethodGet, "/user/gopher/1/about")
+	require.False(t, matched)
+	v, _, matched = router.Match(http.MethodGet, "/users")
+	require.True(t, matched)
+	require.Equal(t, "users", v)
+	require.Equal(t, uint16(1), router.maxParams)
+}
+
+func TestRouterMount(t *testing.T) {
+	sub := New()
+	sub.GET("/", "index")
+	sub.GET("/users/:id", "user")
+	sub.GET("/users/:id/files/*filepath", "files")
+	sub.POST("/users", "create")
+	sub.Disable(http.MethodPost, "/users")
+
+	router := New()
+	router.GET("/", "root")
+	router.Mount("/api/:version/", sub)
+
+	require.Equal(t, []Route{
+		{http.MethodGet, "/", "root", false, ""},
+		{http.MethodGet, "/api/:version/", "index", false, ""},
+		{http.MethodGet, "/api/:version/users/:id", "user", false, ""},
+		{http.MethodGet, "/api/:version/users/:id/files/*filepath", "files", false, ""},
+		{http.MethodPost, "/api/:version/users", "create", true, ""},
+	}, router.Routes())
+
+	res := router.MatchResult(http.MethodGet, "/api/v1/users/42/files/a/b.txt")
+	require.True(t, res.Matched)
+	require.Equal(t, "files", res.Value)
+	require.Equal(t, "/api/:version/users/:id/files/*filepath", res.Route)
+	require.Equal(t, Params{
+		Param{"version", "v1"},
+		Param{"id", "42"},
+		Param{"filepath", "/a/b.txt"},
+	}, res.Params)
+	require.Equal(t, uint16(3), router.maxParams)
+
+	require.Panics(t, func() { router.Mount("api", sub) })
+}
+
+func TestRouterHandle(t *testing.T) {
+	router := New()
+	router.Handle([]string{http.MethodGet, http.MethodPost}, "/form", "form")
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		v, _, matched := router.Match(method, "/form")
+		require.True(t, matched, method)
+		require.Equal(t, "form", v, method)
+	}
+	_, _, matched := router.Match(http.MethodPut, "/form")
+	require.False(t, matched)
+
+	require.Panics(t, func() { router.Handle(nil, "/empty", "empty") })
+	require.Panics(t, func() { router.Handle([]string{http.MethodGet}, "/form", "form") })
+}
+
+func TestRouterAny(t *testing.T) {
+	router := New(WithAnyMethods(http.MethodGet, http.MethodPost))
+	router.Any("/user/:name", "any")
+	router.POST("/user/:name", "post")
+
+	v, ps, matched := router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "any", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+	v, _, matched = router.Match(http.MethodPost, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "post", v)
+	_, _, matched = router.Match(http.MethodPut, "/user/gopher")
+	require.False(t, matched)
+
+	// the any route is stored once
+	require.Equal(t, []Route{
+		{MethodAny, "/user/:name", "any", false, ""},
+		{http.MethodPost, "/user/:name", "post", false, ""},
+	}, router.Routes())
+	require.Equal(t, []string{http.MethodGet, http.MethodPost}, router.AllowedMethods("/user/gopher"))
+
+	// path correction applies to any routes
+	_, _, matched = router.Match(http.MethodGet, "/USER/gopher/")
+	require.True(t, matched)
+	res := router.MatchRedirect(http.MethodGet, "/user/gopher/")
+	require.Equal(t, "/user/gopher", res.Redirect)
+	res = router.MatchResult(http.MethodPut, "/user/gopher")
+	require.True(t, res.MethodNotAllowed)
+}
+
+func TestRouterAnyLookup(t *testing.T) {
+	router := New(WithAnyMethods(http.MethodGet, http.MethodPost))
+	router.Any("/x/:id", "any")
+	router.POST("/y", "post")
+
+	v, ps, _ := router.Lookup(http.MethodGet, "/x/1")
+	require.Equal(t, "any", v)
+	require.Equal(t, Params{{"id", "1"}}, ps)
+	v, _, _ = router.Lookup(http.MethodPut, "/x/1")
+	require.Nil(t, v)
+
+	require.True(t, router.HasRoute(http.MethodGet, "/x/:id"))
+	require.True(t, router.HasRoute(MethodAny, "/x/:id"))
+	require.False(t, router.HasRoute(http.MethodPut, "/x/:id"))
+	require.False(t, router.HasRoute(http.MethodGet, "/y"))
+
+	require.False(t, router.Remove(http.MethodPut, "/x/:id"))
+	require.True(t, router.Remove(http.MethodGet, "/x/:id"))
+	_, _, matched := router.Match(http.MethodGet, "/x/1")
+	require.False(t, matched)
+	_, _, matched = router.Match(http.MethodPost, "/x/1")
+	require.False(t, matched)
+	require.False(t, router.HasRoute(http.MethodGet, "/x/:id"))
+}
+
+func TestRouterAddE(t *testing.T) {
+	router := New()
+
+	require.Error(t, router.AddE("", "/", "v"))
+	require.Error(t, router.AddE(http.MethodGet, "noSlashRoot", "v"))
+	require.Error(t, router.AddE(http.MethodGet, "/", nil))
+	require.Error(t, router.AddE(http.MethodGet, "/:", "v"))
+	require.Empty(t, router.trees)
+
+	require.NoError(t, router.AddE(http.MethodGet, "/user/:name", "user"))
+	require.NoError(t, router.AddE(http.MethodGet, "/users", "users"))
+
+	err := router.AddE(http.MethodGet, "/user/:id/about", "about")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "conflicts with existing wildcard")
+	conflict, ok := err.(*ConflictError)
+	require.True(t, ok)
+	require.Equal(t, "/user/:name", conflict.Existing)
+	require.Equal(t, 1, conflict.Segment)
+	require.EqualError(t, router.AddE(http.MethodGet, "/users", "users"),
+		"a value is already registered for path '/users'")
+
+	// the tree is still consistent after the errors
+	checkPriorities(t, router.trees[http.MethodGet])
+	v, ps, matched := router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+	v, _, matched = router.Match(http.MethodGet, "/users")
+	require.True(t, matched)
+	require.Equal(t, "users", v)
+}
+
+func TestRouterMethodNormalization(t *testing.T) {
+	router := New()
+	router.GET("/x", "x")
+	_, _, matched := router.Match("get", "/x")
+	require.False(t, matched)
+
+	router = New(WithMethodNormalization(), WithMethodAliases(map[string]string{"FETCH": http.MethodGet}))
+	router.Add("get", "/x", "x")
+	router.Add("Post", "/x", "post")
+
+	for _, method := range []string{"GET", "get", "Get", "fetch"} {
+		v, _, matched := router.Match(method, "/x")
+		require.True(t, matched, method)
+		require.Equal(t, "x", v, method)
+	}
+	require.Equal(t, []string{http.MethodGet, http.MethodPost}, router.AllowedMethods("/x"))
+	require.True(t, router.HasRoute("fetch", "/x"))
+	require.True(t, router.MatchResult("get", "/x").Matched)
+	require.Equal(t, "/x", router.MatchRedirect("get", "/x/").Redirect)
+	require.Panics(t, func() { router.Add("GET", "/x", "x") })
+
+	require.True(t, router.Remove("post", "/x"))
+	_, _, matched = router.Match(http.MethodPost, "/x")
+	require.False(t, matched)
+}
+
+func TestRouterRouteOptions(t *testing.T) {
+	router := New()
+	router.Add(http.MethodPost, "/webhook", "webhook", StrictSlash(), NoFixedPath())
+	router.Add(http.MethodPost, "/api", "api")
+
+	v, _, matched := router.Match(http.MethodPost, "/webhook")
+	require.True(t, matched)
+	require.Equal(t, "webhook", v)
+	for _, path := range []string{"/webhook/", "/WEBHOOK", "//webhook"} {
+		_, _, matched = router.Match(http.MethodPost, path)
+		require.False(t, matched, path)
+		require.Empty(t, router.MatchRedirect(http.MethodPost, path).Redirect, path)
+	}
+
+	// the other routes keep the path correction
+	for _, path := range []string{"/api/", "/API", "//api"} {
+		_, _, matched = router.Match(http.MethodPost, path)
+		require.True(t, matched, path)
+		require.Equal(t, "/api", router.MatchRedirect(http.MethodPost, path).Redirect, path)
+	}
+}
+
+func TestRouterNotFoundValue(t *testing.T) {
+	router := New(WithNotFoundValue("default"))
+	router.GET("/user/:name", "user")
+
+	v, ps, matched := router.Match(http.MethodGet, "/missing")
+	require.False(t, matched)
+	require.Equal(t, "default", v)
+	require.Nil(t, ps)
+	v, _, matched = router.MatchURL(http.MethodGet, "/missing")
+	require.False(t, matched)
+	require.Equal(t, "default", v)
+	v, _, matched = router.MatchView(http.MethodGet, "/missing")
+	require.False(t, matched)
+	require.Equal(t, "default", v)
+	res := router.MatchResult(http.MethodPost, "/user/gopher")
+	require.False(t, res.Matched)
+	require.Equal(t, "default", res.Value)
+	require.Equal(t, "default", router.MatchRedirect(http.MethodGet, "/missing").Value)
+
+	v, _, matched = router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+
+	pattern := NewPattern(WithNotFoundValue("default"))
+	v, _, matched = pattern.MatchURL("/missing")
+	require.False(t, matched)
+	require.Equal(t, "default", v)
+}
+
+func TestRouterMatchE(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+
+	v, ps, err := router.MatchE(http.MethodGet, "/user/gopher")
+	require.NoError(t, err)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+	_, _, err = router.MatchE(http.MethodPost, "/user/gopher")
+	require.Equal(t, ErrMethodNotAllowed, err)
+	_, _, err = router.MatchE(http.MethodGet, "/missing")
+	require.Equal(t, ErrNotFound, err)
+
+	res := router.MatchResult(http.MethodGet, "/user/gopher")
+	require.NoError(t, res.Err())
+	res = router.MatchResult(http.MethodPost, "/user/gopher")
+	require.Equal(t, ErrMethodNotAllowed, res.Err())
+	res = router.MatchResult(http.MethodGet, "/missing")
+	require.Equal(t, ErrNotFound, res.Err())
+}
+
+func TestRouterCorrectedPathAllocs(t *testing.T) {
+	router := New()
+	router.GET("/user/:name/files", "files")
+
+	// the params of the failed lookup are reused for the corrected path
+	allocs := testing.AllocsPerRun(100, func() { router.Match(http.MethodGet, "/user/gopher/files/") })
+	require.LessOrEqual(t, allocs, float64(2))
+	// only the corrected path itself is allocated
+	allocs = testing.AllocsPerRun(100, func() { router.Match(http.MethodGet, "/USER/gopher//files") })
+	require.LessOrEqual(t, allocs, float64(3))
+}
+
+func TestRouterAllowEmptyParams(t *testing.T) {
+	router := New(WithAllowEmptyParams())
+	router.GET("/users/:id", "user")
+	router.GET("/users/:id/posts", "posts")
+
+	v, ps, matched := router.Match(http.MethodGet, "/users/")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"id", ""}}, ps)
+	require.True(t, ps.Has("id"))
+
+	v, view, matched := router.MatchView(http.MethodGet, "/users/")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"id", ""}}, view.Params())
+
+	v, ps, matched = router.Match(http.MethodGet, "/users//posts")
+	require.True(t, matched)
+	require.Equal(t, "posts", v)
+	require.Equal(t, Params{Param{"id", ""}}, ps)
+
+	_, _, matched = New().GET("/users/:id", "user").Match(http.MethodGet, "/users/")
+	require.False(t, matched)
+
+	pattern := NewPattern(WithAllowEmptyParams())
+	pattern.Add("/users/:id", "user")
+	_, ps, matched = pattern.Match("/users/")
+	require.True(t, matched)
+	require.Equal(t, Params{Param{"id", ""}}, ps)
+}
+
+func TestRouterTrimCatchAllSlash(t *testing.T) {
+	router := New(WithTrimCatchAllSlash(), WithUnescapeParams())
+	router.GET("/files/*filepath", "files")
+	router.GET("/user/:name/*rest", "user")
+
+	tests := []struct {
+		path string
+		ps   Params
+	}{
+		{"/files/templates/a.html", Params{{"filepath", "templates/a.html"}}},
+		{"/files/", Params{{"filepath", ""}}},
+		{"/files/a%2Fb", Params{{"filepath", "a%2Fb"}}},
+		{"/user/%2Fx/y", Params{{"name", "/x"}, {"rest", "y"}}},
+	}
+	for _, tt := range tests {
+		_, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.True(t, matched, tt.path)
+		require.Equal(t, tt.ps, ps, tt.path)
+	}
+
+	pattern := NewPattern(WithTrimCatchAllSlash())
+	pattern.Add("/files/*filepath", "files")
+	_, ps, matched := pattern.Match("/files/templates/a.html")
+	require.True(t, matched)
+	require.Equal(t, "templates/a.html", ps.Param("filepath"))
+}
+
+func TestRouterMatchedRoutePathKey(t *testing.T) {
+	router := New(WithSaveMatchedRoutePath(), WithMatchedRoutePathKey("routeTemplate"))
+	router.GET("/user/:name", "user")
+
+	_, ps, matched := router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, Params{{"name", "gopher"}, {"routeTemplate", "/user/:name"}}, ps)
+	require.Empty(t, ps.MatchedRoutePath())
+	require.Equal(t, "routeTemplate", router.MatchedRoutePathKey())
+	require.Equal(t, "/user/:name", ps.Param(router.MatchedRoutePathKey()))
+	user := ps.Without(router.MatchedRoutePathKey())
+	require.True(t, user.Equal(Params{{"name", "gopher"}}))
+	require.Empty(t, user.Diff(Params{{"name", "gopher"}}))
+	require.Equal(t, map[string]string{"name": "gopher"}, user.ToMap(true))
+
+	_, matchedPath, _ := router.MatchURL(http.MethodGet, "/user/gopher")
+	require.Equal(t, "/user/:name", matchedPath)
+
+	_, view, _ := router.MatchView(http.MethodGet, "/user/gopher")
+	require.Equal(t, "/user/:name", view.Param("routeTemplate"))
+	require.Empty(t, view.Param(MatchedRoutePathParam))
+	require.Equal(t, ps, view.Params())
+
+	pattern := NewPattern(WithSaveMatchedRoutePath(), WithMatchedRoutePathKey("routeTemplate"))
+	pattern.Add("/user/:name", "user")
+	_, ps, _ = pattern.Match("/user/gopher")
+	require.Equal(t, "/user/:name", ps.Param("routeTemplate"))
+
+	require.Panics(t, func() { WithMatchedRoutePathKey("") })
+	require.Equal(t, MatchedRoutePathParam, New().MatchedRoutePathKey())
+}
+
+func TestRouterMidPathWildcards(t *testing.T) {
+	router := New()
+	router.GET("/orgs/:org/*/settings", "settings")
+	router.GET("/v:version/users/:id", "user")
+	router.GET("/a/*/b/*/c", "c")
+
+	v, ps, matched := router.Match(http.MethodGet, "/orgs/go/team/settings")
+	require.True(t, matched)
+	require.Equal(t, "settings", v)
+	require.Equal(t, Params{{"org", "go"}, {"$1", "team"}}, ps)
+
+	v, ps, matched = router.Match(http.MethodGet, "/v2/users/1")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{{"version", "2"}, {"id", "1"}}, ps)
+
+	_, ps, matched = router.Match(http.MethodGet, "/a/1/b/2/c")
+	require.True(t, matched)
+	require.Equal(t, Params{{"$1", "1"}, {"$2", "2"}}, ps)
+
+	_, _, matched = router.Match(http.MethodGet, "/orgs/go/team/x/settings")
+	require.False(t, matched)
+	require.True(t, router.HasRoute(http.MethodGet, "/orgs/:org/:$1/settings"))
+
+	pattern := NewPattern().Add("/x/*/y", "y")
+	v, ps, matched = pattern.Match("/x/1/y")
+	require.True(t, matched)
+	require.Equal(t, "y", v)
+	require.Equal(t, Params{{"$1", "1"}}, ps)
+
+	// the paths as given to Add name the routes
+	require.True(t, router.HasRoute(http.MethodGet, "/a/*/b/*/c"))
+	require.True(t, router.Update(http.MethodGet, "/a/*/b/*/c", "c2"))
+	require.True(t, router.Disable(http.MethodGet, "/a/*/b/*/c"))
+	require.True(t, router.IsDisabled(http.MethodGet, "/a/:$1/b/:$2/c"))
+	require.True(t, router.Enable(http.MethodGet, "/a/*/b/*/c"))
+	v, _, _ = router.Match(http.MethodGet, "/a/1/b/2/c")
+	require.Equal(t, "c2", v)
+	require.True(t, router.Remove(http.MethodGet, "/a/*/b/*/c"))
+	require.False(t, router.HasRoute(http.MethodGet, "/a/*/b/*/c"))
+	require.False(t, router.HasRoute(http.MethodGet, "*/**"))
+
+	require.True(t, pattern.Update("/x/*/y", "y2"))
+	pattern.Put("/x/*/y", "y3")
+	v, _, _ = pattern.Match("/x/1/y")
+	require.Equal(t, "y3", v)
+	require.True(t, pattern.Remove("/x/*/y"))
+	require.Empty(t, pattern.List())
+}
+
+func TestRouterCatchAllSuffix(t *testing.T) {
+	router := New()
+	router.GET("/files/**/meta.json", "meta")
+	router.GET("/files/*$1", "file")
+	router.GET("/repos/*path/tree/:ref", "tree")
+
+	v, ps, matched := router.Match(http.MethodGet, "/files/a/b/meta.json")
+	require.True(t, matched)
+	require.Equal(t, "meta", v)
+	require.Equal(t, Params{{"$1", "/a/b"}}, ps)
+
+	v, ps, matched = router.Match(http.MethodGet, "/files/meta.json")
+	require.True(t, matched)
+	require.Equal(t, "file", v)
+	require.Equal(t, Params{{"$1", "/meta.json"}}, ps)
+
+	v, ps, matched = router.Match(http.MethodGet, "/repos/go/tools/tree/main")
+	require.True(t, matched)
+	require.Equal(t, "tree", v)
+	require.Equal(t, Params{{"path", "/go/tools"}, {"ref", "main"}}, ps)
+
+	_, _, matched = router.Match(http.MethodGet, "/repos/go/tree")
+	require.False(t, matched)
+
+	require.Panics(t, func() { router.GET("/a/*b/c/*d", "d") })
+}
+
+func TestRouterParamSuffix(t *testing.T) {
+	router := New()
+	router.GET("/reports/:id.json", "report")
+	router.GET("/users/:id<int>.xml", "user").Name("user")
+
+	v, ps, matched := router.Match(http.MethodGet, "/reports/7.json")
+	require.True(t, matched)
+	require.Equal(t, "report", v)
+	require.Equal(t, Params{{"id", "7"}}, ps)
+
+	_, ps, matched = router.Match(http.MethodGet, "/reports/a.b.json")
+	require.True(t, matched)
+	require.Equal(t, Params{{"id", "a.b"}}, ps)
+
+	for _, path := range []string{"/reports/7.csv", "/reports/.json", "/reports/7", "/users/a.xml"} {
+		_, _, matched = router.Match(http.MethodGet, path)
+		require.False(t, matched, path)
+	}
+
+	_, view, matched := router.MatchView(http.MethodGet, "/users/1.xml")
+	require.True(t, matched)
+	require.Equal(t, "1", view.Param("id"))
+
+	url, err := router.URLFor("user", map[string]string{"id": "5"})
+	require.NoError(t, err)
+	require.Equal(t, "/users/5.xml", url)
+
+	require.Panics(t, func() { New().GET("/a/:.json", "a") })
+	_, ok := New().GET("/a/:id.json", "a").AddE(http.MethodGet, "/a/:id", "b").(*ConflictError)
+	require.True(t, ok)
+}
+
+func TestRouterParamSuffixFixedPath(t *testing.T) {
+	router := New()
+	router.GET("/Users/:id.json/", "user")
+	router.GET("/users/:id.json/posts", "posts")
+
+	// the case-insensitive lookup checks the suffixes
+	_, found := router.trees[http.MethodGet].findCaseInsensitivePathFor("/users/abc", true, &router.Options)
+	require.False(t, found)
+	_, _, matched := router.Match(http.MethodGet, "/users/abc")
+	require.False(t, matched)
+	require.Equal(t, "", router.MatchRedirect(http.MethodGet, "/users/abc").Redirect)
+
+	// the case of the suffix is fixed too
+	require.Equal(t, "/Users/7.json/", router.MatchRedirect(http.MethodGet, "/USERS/7.JSON").Redirect)
+	v, ps, matched := router.Match(http.MethodGet, "/USERS/7.JSON")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{{"id", "7"}}, ps)
+
+	pattern := NewPattern()
+	pattern.Add("/Users/:id.json/", "user")
+	pattern.Add("/users/:id.json/posts", "posts")
+	_, _, matched = pattern.Match("/users/abc")
+	require.False(t, matched)
+	v, _, matched = pattern.Match("/USERS/7.json")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+}
+
+func TestRouterEscapes(t *testing.T) {
+	router := New()
+	router.GET(`/v1/projects/:id/\:undelete`, "undelete").Name("undelete")
+	router.GET(`/files/\*`, "star")
+
+	v, ps, matched := router.Match(http.MethodGet, "/v1/projects/7/:undelete")
+	require.True(t, matched)
+	require.Equal(t, "undelete", v)
+	require.Equal(t, Params{{"id", "7"}}, ps)
+
+	v, _, matched = router.Match(http.MethodGet, "/files/*")
+	require.True(t, matched)
+	require.Equal(t, "star", v)
+
+	_, _, matched = router.Match(http.MethodGet, "/files/x")
+	require.False(t, matched)
+
+	url, err := router.URLFor("undelete", map[string]string{"id": "5"})
+	require.NoError(t, err)
+	require.Equal(t, "/v1/projects/5/:undelete", url)
+
+	require.True(t, router.HasRoute(http.MethodGet, `/files/\*`))
+	require.True(t, router.Remove(http.MethodGet, `/files/\*`))
+	require.Equal(t, `/v1/projects/:id/\:undelete`, router.Routes()[0].Path)
+}
@@ -0,0 +1,104 @@
+package wrmatch
+
+import "errors"
+
+// ErrSealed is returned, or panicked with, by the methods modifying a router
+// sealed with Router.Seal.
+var ErrSealed = errors.New("router is sealed")
+
+// sealOptions of Router.Seal
+type sealOptions struct {
+	internStrings bool
+}
+
+// SealOption for Router.Seal
+type SealOption func(*sealOptions)
+
+// InternStrings deduplicates the strings of the trees when sealing, e.g. the
+// param names repeated across routes. The strings are copied, so they no
+// longer retain the memory of the path strings they were sliced from.
+func InternStrings() SealOption {
+	return func(o *sealOptions) {
+		o.internStrings = true
+	}
+}
+
+// Seal makes the router read-only, the methods modifying it panic with
+// ErrSealed afterwards, or return it, if they return an error. The slices of
+// the trees are trimmed to their length and the bookkeeping only needed to
+// register routes is dropped, reducing the steady-state memory of routers
+// built once, e.g. from large configs. A Clone of a sealed router is not
+// sealed.
+// The route counts of the TablePolicy and the memory usage are kept, they
+// take a few words only, MemoryUsage still reports the latter and a Clone
+// checks the routes it registers against both. The names are kept for
+// URLFor.
+// Overlays are not sealed.
+func (r *Router) Seal(opts ...SealOption) *Router {
+	var o sealOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var strs map[string]string
+	if o.internStrings {
+		strs = make(map[string]string)
+	}
+	for _, root := range r.trees {
+		root.seal(strs)
+	}
+	if strs != nil {
+		for name, rn := range r.names {
+			r.names[name] = routeName{intern(strs, rn.method), intern(strs, rn.path)}
+		}
+	}
+	// the route added last is only named by Name
+	r.last = nil
+	r.sealed = true
+	return r
+}
+
+// checkSealed panics with ErrSealed if the router is sealed.
+func (r *Router) checkSealed() {
+	if r.sealed {
+		panic(ErrSealed)
+	}
+}
+
+// seal trims the slices of the tree n and interns its strings into strs,
+// if it is not nil.
+func (n *node) seal(strs map[string]string) {
+	if len(n.children) < cap(n.children) {
+		n.children = append([]*node(nil), n.children...)
+	}
+	if n.meta != nil && len(n.meta.params) < cap(n.meta.params) {
+		n.meta.params = append(Params(nil), n.meta.params...)
+	}
+	if strs != nil {
+		n.path = intern(strs, n.path)
+		n.indices = intern(strs, n.indices)
+		if n.meta != nil {
+			n.meta.route = intern(strs, n.meta.route)
+			n.meta.name = intern(strs, n.meta.name)
+			for i := range n.meta.params {
+				n.meta.params[i].Key = intern(strs, n.meta.params[i].Key)
+				n.meta.params[i].Value = intern(strs, n.meta.params[i].Value)
+			}
+		}
+	}
+	for _, child := range n.children {
+		child.seal(strs)
+	}
+}
+
+// intern returns the copy of s held by strs, which is added on first use.
+func intern(strs map[string]string, s string) string {
+	if s == "" {
+		return ""
+	}
+	if v, ok := strs[s]; ok {
+		return v
+	}
+	v := string([]byte(s))
+	strs[v] = v
+	return v
+}
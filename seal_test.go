@@ -0,0 +1,61 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterSeal(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user").Name("user")
+	router.GET("/user/:name/posts/:id", "posts")
+	router.POST("/team/:name", "team")
+	router.Seal(InternStrings())
+
+	v, ps, matched := router.Match(http.MethodGet, "/user/gopher/posts/1")
+	require.True(t, matched)
+	require.Equal(t, "posts", v)
+	require.Equal(t, Params{Param{"name", "gopher"}, Param{"id", "1"}}, ps)
+	url, err := router.URLFor("user", map[string]string{"name": "gopher"})
+	require.NoError(t, err)
+	require.Equal(t, "/user/gopher", url)
+	require.Equal(t, "/user/gopher", router.MatchRedirect(http.MethodGet, "/user/gopher/").Redirect)
+
+	// the slices are trimmed
+	router.trees[http.MethodGet].walk("", func(_ string, n *node) bool {
+		require.Equal(t, len(n.children), cap(n.children))
+		return true
+	})
+	require.PanicsWithValue(t, ErrSealed, func() { router.GET("/new", "new") })
+	require.PanicsWithValue(t, ErrSealed, func() { router.Remove(http.MethodGet, "/user/:name") })
+	require.PanicsWithValue(t, ErrSealed, func() { router.Disable(http.MethodGet, "/user/:name") })
+	require.PanicsWithValue(t, ErrSealed, func() { router.Name("other") })
+	require.Equal(t, ErrSealed, router.AddE(http.MethodGet, "/new", "new"))
+	require.Equal(t, ErrSealed, router.Merge(New(), ConflictFail))
+
+	// a clone is not sealed
+	c := router.Clone()
+	c.GET("/new", "new")
+	_, _, matched = c.Match(http.MethodGet, "/new")
+	require.True(t, matched)
+
+	// the route counts of the policy and the memory usage are kept
+	router = New(WithPolicy(TablePolicy{MaxRoutes: 2}))
+	router.GET("/a", "a").GET("/b", "b")
+	usage := router.MemoryUsage()
+	router.Seal()
+	require.Nil(t, router.last)
+	require.Equal(t, usage, router.MemoryUsage())
+	require.Error(t, router.Clone().AddE(http.MethodGet, "/c", "c"))
+}
+
+func TestIntern(t *testing.T) {
+	strs := make(map[string]string)
+	path := "/user/:name"
+	require.Equal(t, ":name", intern(strs, path[6:]))
+	require.Equal(t, ":name", intern(strs, ":name"))
+	require.Equal(t, "", intern(strs, ""))
+	require.Len(t, strs, 1)
+}
@@ -0,0 +1,76 @@
+package wrmatch
+
+import "strings"
+
+// WithServeMuxPatterns accepts the wildcards of the net/http ServeMux
+// patterns of Go 1.22 alongside the named parameters and catch-alls, so that
+// route tables written for net/http can be used unchanged. "{name}" is the
+// named parameter ":name", "{name...}" the catch-all "*name" and a trailing
+// "{$}" is dropped, e.g. "/users/{id}/files/{path...}" registers the route
+// "/users/:id/files/*path". Like in net/http, the ':' and '*' of the other
+// parts of the path are literal.
+// A pattern without "{$}" ending with a '/' matches only the path itself,
+// not the paths below it, unlike in net/http. The patterns are converted by
+// the methods adding routes, the methods taking a registered path, e.g.
+// Router.Remove, expect the route path, as returned by Router.Routes.
+// Default: disable
+func WithServeMuxPatterns() Option {
+	return func(r *Options) {
+		r.serveMuxPatterns = true
+	}
+}
+
+// serveMuxPath converts the net/http pattern path to a route path, see
+// WithServeMuxPatterns. It panics if a wildcard is malformed.
+func serveMuxPath(path string) string {
+	if !strings.ContainsAny(path, "{}:*") {
+		return path
+	}
+	var b strings.Builder
+	for rest := path; rest != ""; {
+		i := strings.IndexAny(rest, "{}:*")
+		if i < 0 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:i])
+		switch rest[i] {
+		case ':', '*':
+			b.WriteByte('\\')
+			b.WriteByte(rest[i])
+			rest = rest[i+1:]
+			continue
+		case '}':
+			panic("unexpected '}' in path '" + path + "'")
+		}
+		end := strings.IndexByte(rest[i:], '}')
+		if end < 0 {
+			panic("unclosed wildcard in path '" + path + "'")
+		}
+		name := rest[i+1 : i+end]
+		rest = rest[i+end+1:]
+		switch {
+		case name == "$":
+			if rest != "" {
+				panic("{$} must be at the end of the path '" + path + "'")
+			}
+		case strings.HasSuffix(name, "..."):
+			b.WriteString("*" + name[:len(name)-3])
+		default:
+			b.WriteString(":" + name)
+		}
+		if strings.ContainsAny(name, "{/") || name == "" || name == "..." {
+			panic("malformed wildcard '{" + name + "}' in path '" + path + "'")
+		}
+	}
+	return b.String()
+}
+
+// convertPath converts the net/http pattern path to a route path, if
+// enabled with WithServeMuxPatterns.
+func (o *Options) convertPath(path string) string {
+	if !o.serveMuxPatterns {
+		return path
+	}
+	return serveMuxPath(path)
+}
@@ -0,0 +1,60 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeMuxPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+	}{
+		{"/", "/"},
+		{"/users/{id}", "/users/:id"},
+		{"/users/{id}/files/{path...}", "/users/:id/files/*path"},
+		{"/posts/{$}", "/posts/"},
+		{"/reports/{id}.json", "/reports/:id.json"},
+		{"/v1/projects:undelete", `/v1/projects\:undelete`},
+		{"/files/*", `/files/\*`},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.path, serveMuxPath(tt.pattern), tt.pattern)
+	}
+
+	for _, pattern := range []string{"/users/{id", "/users/id}", "/users/{}", "/users/{...}", "/{$}/a", "/{a{b}"} {
+		require.Panics(t, func() { serveMuxPath(pattern) }, pattern)
+	}
+}
+
+func TestRouterServeMuxPatterns(t *testing.T) {
+	router := New(WithServeMuxPatterns())
+	router.GET("/users/{id}/files/{path...}", "files")
+	router.GET("/v1/projects:undelete", "undelete")
+	router.Static("/assets", "assets")
+
+	v, ps, matched := router.Match(http.MethodGet, "/users/1/files/a/b")
+	require.True(t, matched)
+	require.Equal(t, "files", v)
+	require.Equal(t, Params{{"id", "1"}, {"path", "/a/b"}}, ps)
+
+	v, _, matched = router.Match(http.MethodGet, "/v1/projects:undelete")
+	require.True(t, matched)
+	require.Equal(t, "undelete", v)
+
+	_, _, matched = router.Match(http.MethodGet, "/assets/app.css")
+	require.True(t, matched)
+
+	require.Error(t, router.AddE(http.MethodGet, "/users/{id", "bad"))
+
+	sub := New(WithServeMuxPatterns())
+	sub.Mount("/api", router)
+	require.True(t, sub.HasRoute(http.MethodGet, "/api/users/:id/files/*path"))
+
+	pattern := NewPattern(WithServeMuxPatterns()).Add("/orgs/{org}", "org")
+	_, ps, matched = pattern.Match("/orgs/go")
+	require.True(t, matched)
+	require.Equal(t, Params{{"org", "go"}}, ps)
+}
@@ -0,0 +1,206 @@
+// Package spec defines a language independent JSON format of route table
+// test vectors, so that matchers in other languages can verify they behave
+// identically to wrmatch, e.g.
+//
+//  vectors := spec.Generate(router)
+//  data, _ := json.MarshalIndent(vectors, "", "  ")
+//
+// and, on the side of wrmatch,
+//
+//  func TestSpec(t *testing.T) {
+//      var s spec.Spec
+//      _ = json.Unmarshal(data, &s)
+//      spec.RunSpec(t, wrmatch.New(), s)
+//  }
+//
+// The expected results are the ones of Router.MatchResult, including the
+// enabled path corrections, so the router running a spec should have the
+// options of the router it was generated from.
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wyy-go/wrmatch"
+)
+
+// Spec is a route table and the expected results of matching probe paths.
+type Spec struct {
+	Routes []Route `json:"routes"`
+	Probes []Probe `json:"probes"`
+}
+
+// Route is a registered route, Method is wrmatch.MethodAny for the routes
+// matching the any methods.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Params are added to the params of the matches of the route, e.g. the
+	// defaults of the omitted optional parameters, see
+	// wrmatch.Router.RouteParams.
+	Params []Param `json:"params,omitempty"`
+}
+
+// Probe is a request and its expected match.
+type Probe struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Matched bool   `json:"matched"`
+	// Template is the registered path of the matched route.
+	Template string  `json:"template,omitempty"`
+	Params   []Param `json:"params,omitempty"`
+}
+
+// Param is a url param of a match.
+type Param struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Generate returns the spec of the enabled routes of r. For every route
+// there are probes for a path matching it, and for the path with a toggled
+// trailing slash, their expected results are the ones of r.
+// Overlays are not included.
+func Generate(r *wrmatch.Router) Spec {
+	var s Spec
+	for _, route := range r.Routes() {
+		if route.Disabled {
+			continue
+		}
+		var params []Param
+		for _, p := range r.RouteParams(route.Method, route.Path) {
+			params = append(params, Param{p.Key, p.Value})
+		}
+		s.Routes = append(s.Routes, Route{route.Method, route.Path, params})
+	}
+	for _, route := range s.Routes {
+		method := route.Method
+		if method == wrmatch.MethodAny {
+			method = r.AnyMethods()[0]
+		}
+		path := samplePath(route.Path)
+		toggled := path + "/"
+		if strings.HasSuffix(path, "/") {
+			toggled = strings.TrimSuffix(path, "/")
+		}
+		s.Probes = append(s.Probes, probe(r, method, path))
+		if toggled != "" {
+			s.Probes = append(s.Probes, probe(r, method, toggled))
+		}
+	}
+	return s
+}
+
+// constraintSamples are the sample values of the named parameters with a
+// constraint, e.g. "/user/:id<int>" or "/user/:id@int".
+var constraintSamples = map[string]string{
+	"int":   "1",
+	"uuid":  "123e4567-e89b-12d3-a456-426614174000",
+	"alpha": "abc",
+	"hex":   "1f",
+	"date":  "2006-01-02",
+}
+
+// paramConstraint returns the constraint of a named parameter, e.g. "int"
+// for "id<int>" or "id@int", empty if there is none.
+func paramConstraint(name string) string {
+	if j := strings.IndexByte(name, '<'); j >= 0 {
+		return name[j+1 : len(name)-1]
+	}
+	if j := strings.IndexByte(name, '@'); j >= 0 {
+		return name[j+1:]
+	}
+	return ""
+}
+
+// samplePath returns a request path matching the template, the wildcards
+// are replaced by values derived from their names.
+func samplePath(template string) string {
+	var b strings.Builder
+	for template != "" {
+		i := strings.IndexAny(template, ":*")
+		if i > 0 && template[i-1] == '\\' {
+			// an escaped literal ':' or '*'
+			b.WriteString(template[:i-1] + template[i:i+1])
+			template = template[i+1:]
+			continue
+		}
+		if i < 0 {
+			b.WriteString(template)
+			break
+		}
+		b.WriteString(template[:i])
+		end := strings.IndexByte(template[i:], '/')
+		if end < 0 {
+			end = len(template)
+		} else {
+			end += i
+		}
+		name, suffix := template[i+1:end], ""
+		if k := strings.IndexByte(name, '.'); k >= 0 && template[i] == ':' {
+			// the literal suffix of the param, e.g. ".json"
+			name, suffix = name[:k], name[k:]
+		}
+		if template[i] == '*' {
+			b.WriteString(name + "/x")
+		} else if sample := constraintSamples[paramConstraint(name)]; sample != "" {
+			b.WriteString(sample)
+		} else {
+			b.WriteString(name + "-1")
+		}
+		b.WriteString(suffix)
+		template = template[end:]
+	}
+	return b.String()
+}
+
+// probe returns the probe of method and path with the result of r.
+func probe(r *wrmatch.Router, method, path string) Probe {
+	res := r.MatchResult(method, path)
+	p := Probe{Method: method, Path: path, Matched: res.Matched, Template: res.Route}
+	for _, param := range res.Params {
+		if param.Key != wrmatch.MatchedRoutePathParam {
+			p.Params = append(p.Params, Param{param.Key, param.Value})
+		}
+	}
+	return p
+}
+
+// RunSpec adds the routes of the spec to r, their values are their paths,
+// and reports every probe whose match differs from the expected one.
+func RunSpec(t testing.TB, r *wrmatch.Router, s Spec) {
+	t.Helper()
+	for _, route := range s.Routes {
+		if err := r.AddE(route.Method, route.Path, route.Path); err != nil {
+			t.Fatalf("route %s %s: %v", route.Method, route.Path, err)
+		}
+		if len(route.Params) > 0 {
+			var params wrmatch.Params
+			for _, p := range route.Params {
+				params = append(params, wrmatch.Param{Key: p.Key, Value: p.Value})
+			}
+			r.SetRouteParams(route.Method, route.Path, params)
+		}
+	}
+	for _, want := range s.Probes {
+		if got := probe(r, want.Method, want.Path); !equal(got, want) {
+			t.Errorf("probe %s %s: got %+v, want %+v", want.Method, want.Path, got, want)
+		}
+	}
+}
+
+// equal reports whether the probes have the same result, nil and empty
+// params are equal.
+func equal(a, b Probe) bool {
+	if a.Method != b.Method || a.Path != b.Path || a.Matched != b.Matched ||
+		a.Template != b.Template || len(a.Params) != len(b.Params) {
+		return false
+	}
+	for i := range a.Params {
+		if a.Params[i] != b.Params[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,94 @@
+package spec
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wyy-go/wrmatch"
+)
+
+func newRouter() *wrmatch.Router {
+	router := wrmatch.New()
+	router.GET("/", "index")
+	router.GET("/user/:name", "user")
+	router.GET("/user/:name/files/*filepath", "files")
+	router.POST("/dir/", "dir")
+	router.GET("/post/:day<date>", "post")
+	router.Any("/any", "any")
+	router.GET("/disabled", "disabled")
+	router.Disable(http.MethodGet, "/disabled")
+	router.GET("/page/:n=1", "page")
+	return router
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(newRouter())
+
+	require.Equal(t, []Route{
+		{wrmatch.MethodAny, "/any", nil},
+		{http.MethodGet, "/", nil},
+		{http.MethodGet, "/page", []Param{{"n", "1"}}},
+		{http.MethodGet, "/page/:n", nil},
+		{http.MethodGet, "/post/:day<date>", nil},
+		{http.MethodGet, "/user/:name", nil},
+		{http.MethodGet, "/user/:name/files/*filepath", nil},
+		{http.MethodPost, "/dir/", nil},
+	}, s.Routes)
+	require.Contains(t, s.Probes, Probe{
+		Method:   http.MethodGet,
+		Path:     "/page",
+		Matched:  true,
+		Template: "/page",
+		Params:   []Param{{"n", "1"}},
+	})
+	require.Contains(t, s.Probes, Probe{
+		Method:   http.MethodGet,
+		Path:     "/user/name-1/files/filepath/x",
+		Matched:  true,
+		Template: "/user/:name/files/*filepath",
+		Params:   []Param{{"name", "name-1"}, {"filepath", "/filepath/x"}},
+	})
+	require.Contains(t, s.Probes, Probe{
+		Method:   http.MethodGet,
+		Path:     "/post/2006-01-02",
+		Matched:  true,
+		Template: "/post/:day<date>",
+		Params:   []Param{{"day", "2006-01-02"}},
+	})
+	require.Contains(t, s.Probes, Probe{
+		Method:   http.MethodPost,
+		Path:     "/dir",
+		Matched:  true,
+		Template: "/dir/",
+	})
+	require.Contains(t, s.Probes, Probe{
+		Method:   http.MethodGet,
+		Path:     "/any",
+		Matched:  true,
+		Template: "/any",
+	})
+}
+
+func TestRunSpec(t *testing.T) {
+	data, err := json.Marshal(Generate(newRouter()))
+	require.NoError(t, err)
+	var s Spec
+	require.NoError(t, json.Unmarshal(data, &s))
+	RunSpec(t, wrmatch.New(), s)
+
+	// a router with other options deviates
+	s.Probes = append(s.Probes, Probe{Method: http.MethodGet, Path: "/USER/a", Matched: false})
+	rec := &recorder{TB: t}
+	RunSpec(rec, wrmatch.New(), s)
+	require.Equal(t, 1, rec.errors)
+}
+
+// recorder counts the errors instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors int
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) { r.errors++ }
@@ -0,0 +1,53 @@
+package wrmatch
+
+import "sync/atomic"
+
+// RouteState is a slot of mutable state attached to a route, e.g. for
+// middlewares implementing per-route circuit breakers or concurrency
+// limits without a separate map keyed by the route. All methods are safe for
+// concurrent use. The state is shared by the copies of the route made by
+// Router.Clone and Router.Merge.
+type RouteState struct {
+	// counter is first, to be 64-bit aligned for the atomic operations
+	counter int64
+	value   atomic.Value
+}
+
+// Add adds delta to the counter of the state and returns the new counter.
+func (s *RouteState) Add(delta int64) int64 {
+	return atomic.AddInt64(&s.counter, delta)
+}
+
+// Counter returns the counter of the state.
+func (s *RouteState) Counter() int64 {
+	return atomic.LoadInt64(&s.counter)
+}
+
+// Load returns the value of the state set with Store, nil if there is none.
+func (s *RouteState) Load() interface{} {
+	return s.value.Load()
+}
+
+// Store sets the value of the state. Like for atomic.Value, all values must
+// have the same concrete type and must not be nil.
+func (s *RouteState) Store(v interface{}) {
+	s.value.Store(v)
+}
+
+// State returns the state of the route registered with the given method and
+// path, nil if there is no such route. The path must be the registered
+// pattern, not a request path.
+func (r *Router) State(method, path string) *RouteState {
+	if n := r.findRoute(method, path); n != nil {
+		return n.routeMeta().state
+	}
+	return nil
+}
+
+// State returns the state of the matched route, nil if no route matched.
+func (m *MatchResult) State() *RouteState {
+	if m.meta == nil {
+		return nil
+	}
+	return m.meta.state
+}
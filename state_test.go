@@ -0,0 +1,43 @@
+package wrmatch
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteState(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/team/:name", "team")
+
+	require.Nil(t, router.State(http.MethodGet, "/missing"))
+	state := router.State(http.MethodGet, "/user/:name")
+	require.NotNil(t, state)
+	require.NotSame(t, state, router.State(http.MethodGet, "/team/:name"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := router.MatchResult(http.MethodGet, "/user/gopher")
+			res.State().Add(1)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int64(10), state.Counter())
+
+	require.Nil(t, state.Load())
+	state.Store("open")
+	res := router.MatchResult(http.MethodGet, "/user/gopher")
+	require.Equal(t, "open", res.State().Load())
+
+	// clones share the state
+	require.Same(t, state, router.Clone().State(http.MethodGet, "/user/:name"))
+
+	res = router.MatchResult(http.MethodGet, "/missing")
+	require.Nil(t, res.State())
+}
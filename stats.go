@@ -0,0 +1,84 @@
+package wrmatch
+
+import "sync"
+
+// WithWildcardStats enables the statistics of the values matched by the
+// wildcards of the routes, Router.WildcardReport reports the number of
+// distinct values observed per wildcard, e.g. to find templates which
+// should be split or constrained. At most limit distinct values are tracked
+// per wildcard, the reported cardinality saturates at limit. The matches
+// of a route are serialized briefly to record the values.
+// Default: disable
+func WithWildcardStats(limit int) Option {
+	if limit < 1 {
+		panic("wildcard stats limit must be positive")
+	}
+	return func(r *Options) {
+		r.wildcardStats = limit
+	}
+}
+
+// wildcardStats holds the distinct values matched by the wildcards of a
+// route, see WithWildcardStats.
+type wildcardStats struct {
+	limit int
+
+	mu sync.Mutex
+	// values by wildcard name
+	values map[string]map[string]struct{}
+}
+
+// newWildcardStats returns the stats of a route, nil if they are disabled
+// or the route has no wildcards.
+func (o *Options) newWildcardStats(path string) *wildcardStats {
+	if o.wildcardStats < 1 || countParams(path) == 0 {
+		return nil
+	}
+	return &wildcardStats{limit: o.wildcardStats}
+}
+
+// record records the values of the params of a match, the params of the
+// view if ps is nil.
+func (s *wildcardStats) record(ps *Params, view *ParamsView) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]map[string]struct{})
+	}
+	if ps != nil {
+		for _, p := range *ps {
+			s.add(p.Key, p.Value)
+		}
+	} else if view != nil {
+		for i := range view.spans {
+			s.add(view.Key(i), view.Value(i))
+		}
+	}
+}
+
+// add adds a value of the wildcard key, s.mu must be held.
+func (s *wildcardStats) add(key, value string) {
+	values := s.values[key]
+	if values == nil {
+		values = make(map[string]struct{})
+		s.values[key] = values
+	}
+	if len(values) < s.limit {
+		values[value] = struct{}{}
+	}
+}
+
+// cardinality returns the number of distinct values of the named
+// parameters and the catch-all of the route by their name.
+func (s *wildcardStats) cardinality(params []string, catchAll string) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := make(map[string]int, len(params)+1)
+	for _, name := range params {
+		c[name] = len(s.values[name])
+	}
+	if catchAll != "" {
+		c[catchAll] = len(s.values[catchAll])
+	}
+	return c
+}
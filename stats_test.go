@@ -0,0 +1,67 @@
+package wrmatch
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterWildcardStats(t *testing.T) {
+	router := New(WithWildcardStats(3))
+	router.GET("/user/:id<int>/files/*filepath", "files")
+	router.GET("/user/:id<int>", "user")
+	router.GET("/about", "about")
+
+	for _, path := range []string{"/user/1", "/user/2", "/user/1", "/user/1/files/a", "/about"} {
+		_, _, matched := router.Match(http.MethodGet, path)
+		require.True(t, matched, path)
+	}
+	_, _, matched := router.MatchView(http.MethodGet, "/user/3")
+	require.True(t, matched)
+
+	require.Equal(t, []WildcardRoute{
+		{
+			Method:      http.MethodGet,
+			Path:        "/user/:id<int>",
+			Params:      []string{"id"},
+			Cardinality: map[string]int{"id": 3},
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/user/:id<int>/files/*filepath",
+			Params:      []string{"id"},
+			CatchAll:    "filepath",
+			Cardinality: map[string]int{"id": 1, "filepath": 1},
+		},
+	}, router.WildcardReport())
+
+	// the cardinality saturates at the limit
+	router.Match(http.MethodGet, "/user/4")
+	require.Equal(t, map[string]int{"id": 3}, router.WildcardReport()[0].Cardinality)
+
+	// the stats are shared with clones, like the route state
+	router.Clone().Match(http.MethodGet, "/user/1/files/b")
+	require.Equal(t, map[string]int{"id": 1, "filepath": 2}, router.WildcardReport()[1].Cardinality)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				router.Match(http.MethodGet, "/user/1/files/c")
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 3, router.WildcardReport()[1].Cardinality["filepath"])
+
+	built, err := BuildParallel([]Route{{Method: http.MethodGet, Path: "/user/:id", Value: "user"}}, 2, WithWildcardStats(3))
+	require.NoError(t, err)
+	built.Match(http.MethodGet, "/user/1")
+	require.Equal(t, map[string]int{"id": 1}, built.WildcardReport()[0].Cardinality)
+
+	require.Panics(t, func() { WithWildcardStats(0) })
+}
@@ -0,0 +1,174 @@
+package wrmatch
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MatchTrace describes the traversal of a slow match, see WithSlowMatch.
+type MatchTrace struct {
+	Method string
+	Path   string
+	// Duration of the match.
+	Duration time.Duration
+	// Steps are the tree lookups of the match in order. Every step after
+	// the first one is a retry, e.g. with a corrected path or in another tree.
+	Steps []TraceStep
+	// Route is the matched route like for WithOnMatch, the zero RouteInfo
+	// if no route matched.
+	Route RouteInfo
+}
+
+// TraceStep is a single tree lookup of a match.
+type TraceStep struct {
+	// Tree is the method of the tree, MethodAny for the routes added with Any.
+	Tree string
+	// Path is the looked up path.
+	Path string
+	// Nodes are the paths of the visited nodes.
+	Nodes   []string
+	Matched bool
+}
+
+// Retries returns the number of lookups after the first one.
+func (t *MatchTrace) Retries() int {
+	if len(t.Steps) == 0 {
+		return 0
+	}
+	return len(t.Steps) - 1
+}
+
+// WithSlowMatch set a function which is called with the traversal trace of
+// every match taking longer than threshold, to find the pathological
+// patterns in production. The trace is recorded by a second traversal after
+// the slow match, so matches which are not slow are only timed.
+// fn is called synchronously by the matching goroutine.
+// Default: none
+func WithSlowMatch(threshold time.Duration, fn func(trace MatchTrace)) Option {
+	return func(r *Options) {
+		r.slowMatchThreshold = threshold
+		r.slowMatch = fn
+	}
+}
+
+// traceMatch records the traversal of match for method and path.
+func (r *Router) traceMatch(method, path string, d time.Duration) MatchTrace {
+	t := MatchTrace{Method: method, Path: path, Duration: d}
+	if _, rest, ok := r.splitLocale(path); ok && r.tracePath(method, rest, &t) {
+		return t
+	}
+	r.tracePath(method, path, &t)
+	return t
+}
+
+// tracePath records the traversal of matchPath into t and reports whether
+// the path matched.
+func (r *Router) tracePath(method, path string, t *MatchTrace) bool {
+	return r.traceExact(method, path, t) || r.traceCorrected(method, path, t)
+}
+
+// traceExact records the traversal of matchExact into t and reports whether
+// the path matched.
+func (r *Router) traceExact(method, path string, t *MatchTrace) bool {
+	for i := len(r.overlays) - 1; i >= 0; i-- {
+		if r.overlays[i].traceExact(method, path, t) {
+			return true
+		}
+	}
+	if r.traceTree(method, path, t) {
+		return true
+	}
+	if method != MethodAny && r.trees[MethodAny] != nil && r.isAnyMethod(method) {
+		return r.traceTree(MethodAny, path, t)
+	}
+	return false
+}
+
+// traceCorrected records the traversal of matchCorrected into t and reports
+// whether the path matched.
+func (r *Router) traceCorrected(method, path string, t *MatchTrace) bool {
+	for i := len(r.overlays) - 1; i >= 0; i-- {
+		if r.overlays[i].traceCorrected(method, path, t) {
+			return true
+		}
+	}
+	if r.traceCorrection(method, method, path, t) {
+		return true
+	}
+	if method != MethodAny && r.trees[MethodAny] != nil && r.isAnyMethod(method) {
+		return r.traceCorrection(MethodAny, method, path, t)
+	}
+	return false
+}
+
+// traceTree records the traversal of matchTree into t and reports whether
+// the path matched.
+func (r *Router) traceTree(key, path string, t *MatchTrace) bool {
+	root := r.trees[key]
+	if root == nil {
+		return false
+	}
+	leaf, _, _ := r.lookup(key, root, path, nil, nil)
+	t.Steps = append(t.Steps, TraceStep{
+		Tree:    key,
+		Path:    path,
+		Nodes:   root.tracePath(path),
+		Matched: leaf != nil,
+	})
+	return leaf != nil
+}
+
+// traceCorrection records the traversal of correctTree into t and reports
+// whether the corrected path matched.
+func (r *Router) traceCorrection(key, method, path string, t *MatchTrace) bool {
+	root := r.trees[key]
+	if root == nil || method == http.MethodConnect || path == "/" {
+		return false
+	}
+	_, _, tsr := r.lookup(key, root, path, nil, nil)
+	opts := r.pathOptions(path)
+	if tsr && opts.redirectTrailingSlash {
+		if len(path) > 1 && path[len(path)-1] == '/' {
+			path = path[:len(path)-1]
+		} else {
+			path += "/"
+		}
+		return r.traceExact(method, path, t)
+	}
+	if fixedPath, found := opts.fixPath(root, path); found && fixedPath != path {
+		return r.traceExact(method, fixedPath, t)
+	}
+	return false
+}
+
+// tracePath returns the paths of the nodes visited by a lookup of path.
+func (n *node) tracePath(path string) []string {
+	var nodes []string
+walk:
+	for {
+		nodes = append(nodes, n.path)
+		if len(path) <= len(n.path) || path[:len(n.path)] != n.path {
+			return nodes
+		}
+		path = path[len(n.path):]
+		if !n.wildChild {
+			for i, c := range []byte(n.indices) {
+				if c == path[0] {
+					n = n.children[i]
+					continue walk
+				}
+			}
+			return nodes
+		}
+
+		n = n.children[0]
+		nodes = append(nodes, n.path)
+		end := strings.IndexByte(path, '/')
+		if n.nType == catchAll || end < 0 || len(n.children) == 0 {
+			return nodes
+		}
+		path = path[end:]
+		n = n.children[0]
+	}
+}
@@ -0,0 +1,51 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterSlowMatch(t *testing.T) {
+	var traces []MatchTrace
+	router := New(WithSlowMatch(-1, func(trace MatchTrace) {
+		traces = append(traces, trace)
+	}))
+	router.GET("/user/:name/posts", "posts")
+	router.GET("/files/*filepath", "files")
+
+	_, _, matched := router.Match(http.MethodGet, "/user/gopher/posts")
+	require.True(t, matched)
+	require.Len(t, traces, 1)
+	require.Equal(t, http.MethodGet, traces[0].Method)
+	require.Equal(t, "/user/gopher/posts", traces[0].Path)
+	require.Equal(t, []TraceStep{
+		{http.MethodGet, "/user/gopher/posts", []string{"/", "user/", ":name", "/posts"}, true},
+	}, traces[0].Steps)
+	require.Equal(t, 0, traces[0].Retries())
+	require.Equal(t, RouteInfo{Methods: []string{http.MethodGet}, Path: "/user/:name/posts", Value: "posts"}, traces[0].Route)
+
+	// the corrections are retries
+	_, _, matched = router.Match(http.MethodGet, "/USER/gopher/posts/")
+	require.True(t, matched)
+	require.Len(t, traces, 2)
+	require.Equal(t, 1, traces[1].Retries())
+	require.False(t, traces[1].Steps[0].Matched)
+	require.Equal(t, "/user/gopher/posts", traces[1].Steps[1].Path)
+	require.True(t, traces[1].Steps[1].Matched)
+
+	_, _, matched = router.Match(http.MethodGet, "/files/a/b")
+	require.True(t, matched)
+	require.Len(t, traces, 3)
+	require.Equal(t, []string{"/", "files", "", "/*filepath"}, traces[2].Steps[0].Nodes)
+
+	// fast matches are not traced
+	router = New(WithSlowMatch(time.Hour, func(trace MatchTrace) {
+		t.Fatal("unexpected slow match")
+	}))
+	router.GET("/", "index")
+	_, _, matched = router.Match(http.MethodGet, "/")
+	require.True(t, matched)
+}
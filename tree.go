@@ -5,6 +5,8 @@
 package wrmatch
 
 import (
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -26,13 +28,90 @@ func longestCommonPrefix(a, b string) int {
 	return i
 }
 
+// commonPrefix is longestCommonPrefix of the route path and the path of a
+// static node, whose escaped ':' and '*' match the literal ones of the node.
+// It returns the length of the prefix in path and in literal.
+func commonPrefix(path, literal string) (int, int) {
+	i, j := 0, 0
+	for i < len(path) && j < len(literal) {
+		c, k := path[i], 1
+		if isEscape(path, i) {
+			c, k = path[i+1], 2
+		} else if c == ':' || c == '*' {
+			break
+		}
+		if c != literal[j] {
+			break
+		}
+		i += k
+		j++
+	}
+	return i, j
+}
+
+// isEscape reports whether the byte at i of the route path is a '\'
+// escaping a literal ':' or '*'.
+func isEscape(path string, i int) bool {
+	return path[i] == '\\' && i+1 < len(path) && (path[i+1] == ':' || path[i+1] == '*')
+}
+
+// firstByte returns the first byte of the route path, the escaped one for an
+// escaped ':' or '*'.
+func firstByte(path string) byte {
+	if isEscape(path, 0) {
+		return path[1]
+	}
+	return path[0]
+}
+
+// unescapePath replaces the escaped ':' and '*' of the route path, e.g.
+// "/a\:b", by literal ones.
+func unescapePath(path string) string {
+	if !strings.Contains(path, "\\:") && !strings.Contains(path, "\\*") {
+		return path
+	}
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if isEscape(path, i) {
+			i++
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// escapePath escapes the literal ':' and '*' of the path of a static node.
+func escapePath(path string) string {
+	if !strings.ContainsAny(path, ":*") {
+		return path
+	}
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == ':' || path[i] == '*' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// routePath returns the path of n as part of a route path, the path of a
+// static node is escaped.
+func (n *node) routePath() string {
+	if n.nType == param || n.nType == catchAll {
+		return n.path
+	}
+	return escapePath(n.path)
+}
+
 // Search for a wildcard segment and check the name for invalid characters.
+// Escaped ':' and '*' do not start a wildcard.
 // Returns -1 as index, if no wildcard was found.
 func findWildcard(path string) (wildcard string, i int, valid bool) {
 	// Find start
 	for start, c := range []byte(path) {
 		// A wildcard starts with ':' (param) or '*' (catch-all)
-		if c != ':' && c != '*' {
+		if c != ':' && c != '*' || start > 0 && path[start-1] == '\\' {
 			continue
 		}
 
@@ -51,17 +130,141 @@ func findWildcard(path string) (wildcard string, i int, valid bool) {
 	return "", -1, false
 }
 
+// countParams returns the number of wildcards of path, an escaped ':' or
+// '*' is not counted.
 func countParams(path string) uint16 {
 	var n uint
-	for i := range []byte(path) {
+	for i := 0; i < len(path); i++ {
 		switch path[i] {
 		case ':', '*':
 			n++
+		case '\\':
+			if isEscape(path, i) {
+				i++
+			}
 		}
 	}
 	return uint16(n)
 }
 
+// nameWildcards names the anonymous wildcards of path, the "*" segments
+// which are not the last one, matching a single segment, and the "**"
+// segments, matching one or more segments like a catch-all, e.g.
+// "/orgs/:org/*/settings" becomes "/orgs/:org/:$1/settings" and
+// "/files/**/meta.json" becomes "/files/*$1/meta.json". The params of the
+// n-th of them have the key "$n".
+func nameWildcards(path string) string {
+	if !strings.Contains(path, "/*/") && !strings.Contains(path, "/**") {
+		return path
+	}
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(path); i++ {
+		if path[i] == '*' && i > 0 && path[i-1] == '/' {
+			if i+1 < len(path) && path[i+1] == '/' {
+				n++
+				b.WriteString(":$" + strconv.Itoa(n))
+				continue
+			}
+			if strings.HasPrefix(path[i:], "**") && (i+2 == len(path) || path[i+2] == '/') {
+				n++
+				b.WriteString("*$" + strconv.Itoa(n))
+				i++
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// checkWildcards panics if a wildcard name is used more than once in path,
+// the params of a match would be ambiguous, if it has more than one
+// catch-all, or if a wildcard has a malformed constraint, e.g.
+// "/user/:id<int".
+func checkWildcards(path string) {
+	var names []string
+	for p := path; ; {
+		wildcard, i, _ := findWildcard(p)
+		if i < 0 {
+			return
+		}
+		name := wildcard[1:]
+		if wildcard[0] == ':' {
+			name, _ = splitSuffix(name)
+			if name == "" {
+				panic("wildcards must be named with a non-empty name in path '" + path + "'")
+			}
+		}
+		full := name
+		name, constraint := splitConstraint(full)
+		if constraint != "" || strings.ContainsAny(full, "<>@") {
+			if wildcard[0] == '*' {
+				panic("catch-all wildcards can not have a constraint in path '" + path + "'")
+			}
+			if constraint == "" || strings.ContainsAny(name, "<>@") || strings.ContainsAny(constraint, "<>@") {
+				panic("malformed constraint of wildcard '" + name + "' in path '" + path + "'")
+			}
+		}
+		if wildcard[0] == '*' && strings.IndexByte(p[i+len(wildcard):], '*') >= 0 {
+			panic("only one catch-all per path is allowed in path '" + path + "'")
+		}
+		for _, seen := range names {
+			if name == seen && name != "" {
+				panic("wildcard name '" + name + "' is used more than once in path '" + path + "'")
+			}
+		}
+		names = append(names, name)
+		p = p[i+len(wildcard):]
+	}
+}
+
+// splitSuffix splits the name of a named parameter with a literal suffix,
+// which starts with a '.', e.g. "id.json" into "id" and ".json". The
+// suffix follows the constraint, e.g. "id<int>.json".
+func splitSuffix(name string) (string, string) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return name[:i], name[i:]
+	}
+	return name, ""
+}
+
+// ConflictError is the panic value (and the error returned by Router.AddE)
+// if a wildcard of a new path conflicts with an already registered path.
+type ConflictError struct {
+	// Path is the path which was added.
+	Path string
+	// Existing is a registered path which conflicts with Path.
+	Existing string
+	// Segment is the zero-based index of the conflicting segment in Path.
+	Segment int
+
+	msg string
+}
+
+func (e *ConflictError) Error() string {
+	return e.msg
+}
+
+// newConflictError builds a ConflictError for fullPath, which conflicts at
+// byte offset off with the routes below n. prefix is the path up to n.
+func newConflictError(fullPath string, off int, prefix string, n *node, msg string) *ConflictError {
+	existing := prefix
+	for {
+		existing += n.routePath()
+		if n.value != nil || len(n.children) == 0 {
+			break
+		}
+		n = n.children[0]
+	}
+	return &ConflictError{
+		Path:     fullPath,
+		Existing: existing,
+		Segment:  strings.Count(fullPath[:off+1], "/") - 1,
+		msg:      msg,
+	}
+}
+
 type nodeType uint8
 
 const (
@@ -79,6 +282,57 @@ type node struct {
 	priority  uint32
 	children  []*node
 	value     interface{}
+	meta      *routeMeta
+}
+
+// routeMeta holds the optional metadata of a registered route,
+// it is stored alongside the value of the node.
+type routeMeta struct {
+	// disabled routes stay registered, but are excluded from matching.
+	disabled bool
+	// cacheHint set with Router.SetCacheHint
+	cacheHint *CacheHint
+	// route is the registered path, e.g. "/user/:name".
+	route string
+	// name set with Router.Name
+	name string
+	// params are appended to the url params of a match, e.g. the version of
+	// Router.MountVersions.
+	params Params
+	// strictSlash set with StrictSlash
+	strictSlash bool
+	// noFixedPath set with NoFixedPath
+	noFixedPath bool
+	// state returned by Router.State
+	state *RouteState
+	// stats of the wildcard values, see WithWildcardStats.
+	stats *wildcardStats
+	// values are the values added after the first one, see WithAppendValues.
+	values []interface{}
+}
+
+// routeName returns the name of the route of n, if any.
+func (n *node) routeName() string {
+	if n.meta == nil {
+		return ""
+	}
+	return n.meta.name
+}
+
+// routeMeta returns the metadata of n, it is allocated on first use.
+func (n *node) routeMeta() *routeMeta {
+	if n.meta == nil {
+		n.meta = &routeMeta{state: new(RouteState)}
+	}
+	return n.meta
+}
+
+// activeValue returns the value of n, or nil if the route is disabled.
+func (n *node) activeValue() interface{} {
+	if n.meta != nil && n.meta.disabled {
+		return nil
+	}
+	return n.value
 }
 
 // Increments priority of the given child and reorders if necessary
@@ -108,6 +362,7 @@ func (n *node) incrementChildPriority(pos int) int {
 // Not concurrency-safe!
 func (n *node) addRoute(path string, value interface{}) {
 	fullPath := path
+	checkWildcards(fullPath)
 	n.priority++
 
 	// Empty tree
@@ -122,25 +377,33 @@ walk:
 		// Find the longest common prefix.
 		// This also implies that the common prefix contains no ':' or '*'
 		// since the existing key can't contain those chars.
+		// i is the length of the prefix in path, j in the path of n,
+		// they differ by the escapes of literal ':' and '*'
 		i := longestCommonPrefix(path, n.path)
+		j := i
+		if n.nType != param && n.nType != catchAll {
+			i, j = commonPrefix(path, n.path)
+		}
 
 		// Split edge
-		if i < len(n.path) {
+		if j < len(n.path) {
 			child := node{
-				path:      n.path[i:],
+				path:      n.path[j:],
 				wildChild: n.wildChild,
 				nType:     static,
 				indices:   n.indices,
 				children:  n.children,
 				value:     n.value,
+				meta:      n.meta,
 				priority:  n.priority - 1,
 			}
 
 			n.children = []*node{&child}
 			// []byte for proper unicode char conversion, see #65
-			n.indices = string([]byte{n.path[i]})
-			n.path = path[:i]
+			n.indices = string([]byte{n.path[j]})
+			n.path = n.path[:j]
 			n.value = nil
+			n.meta = nil
 			n.wildChild = false
 		}
 
@@ -154,8 +417,6 @@ walk:
 
 				// Check if the wildcard matches
 				if len(path) >= len(n.path) && n.path == path[:len(n.path)] &&
-					// Adding a child to a catchAll is not possible
-					n.nType != catchAll &&
 					// Check for longer wildcard, e.g. :name and :names
 					(len(n.path) >= len(path) || path[len(n.path)] == '/') {
 					continue walk
@@ -165,18 +426,22 @@ walk:
 				if n.nType != catchAll {
 					pathSeg = strings.SplitN(pathSeg, "/", 2)[0]
 				}
-				prefix := fullPath[:strings.Index(fullPath, pathSeg)] + n.path
-				panic("'" + pathSeg +
-					"' in new path '" + fullPath +
-					"' conflicts with existing wildcard '" + n.path +
-					"' in existing prefix '" + prefix +
-					"'")
+				off := strings.Index(fullPath, pathSeg)
+				prefix := fullPath[:off] + n.path
+				panic(newConflictError(fullPath, off, fullPath[:off], n, "'"+pathSeg+
+					"' in new path '"+fullPath+
+					"' conflicts with existing wildcard '"+n.path+
+					"' in existing prefix '"+prefix+
+					"'"))
 			}
 
-			idxc := path[0]
+			// An escaped ':' or '*' is indexed like any other byte, a
+			// wildcard is never indexed
+			idxc := firstByte(path)
+			wild := path[0] == ':' || path[0] == '*'
 
-			// '/' after param
-			if n.nType == param && idxc == '/' && len(n.children) == 1 {
+			// '/' after param or catchAll
+			if (n.nType == param || n.nType == catchAll) && idxc == '/' && len(n.children) == 1 {
 				n = n.children[0]
 				n.priority++
 				continue walk
@@ -184,7 +449,7 @@ walk:
 
 			// Check if a child with the next path byte exists
 			for i, c := range []byte(n.indices) {
-				if c == idxc {
+				if c == idxc && !wild {
 					i = n.incrementChildPriority(i)
 					n = n.children[i]
 					continue walk
@@ -192,7 +457,7 @@ walk:
 			}
 
 			// Otherwise insert it
-			if idxc != ':' && idxc != '*' {
+			if !wild {
 				// []byte for proper unicode char conversion, see #65
 				n.indices += string([]byte{idxc})
 				child := &node{}
@@ -235,15 +500,17 @@ func (n *node) insertChild(path, fullPath string, value interface{}) {
 		// Check if this node has existing children which would be
 		// unreachable if we insert the wildcard here
 		if len(n.children) > 0 {
-			panic("wildcard segment '" + wildcard +
-				"' conflicts with existing children in path '" + fullPath + "'")
+			off := len(fullPath) - len(path) + i
+			panic(newConflictError(fullPath, off, fullPath[:len(fullPath)-len(path)], n.children[0],
+				"wildcard segment '"+wildcard+
+					"' conflicts with existing children in path '"+fullPath+"'"))
 		}
 
 		// param
 		if wildcard[0] == ':' {
 			if i > 0 {
 				// Insert prefix before the current wildcard
-				n.path = path[:i]
+				n.path = unescapePath(path[:i])
 				path = path[i:]
 			}
 
@@ -274,10 +541,6 @@ func (n *node) insertChild(path, fullPath string, value interface{}) {
 		}
 
 		// catchAll
-		if i+len(wildcard) != len(path) {
-			panic("catch-all routes are only allowed at the end of the path in path '" + fullPath + "'")
-		}
-
 		if len(n.path) > 0 && n.path[len(n.path)-1] == '/' {
 			panic("catch-all conflicts with existing value for the path segment root in path '" + fullPath + "'")
 		}
@@ -288,7 +551,7 @@ func (n *node) insertChild(path, fullPath string, value interface{}) {
 			panic("no / before catch-all in path '" + fullPath + "'")
 		}
 
-		n.path = path[:i]
+		n.path = unescapePath(path[:i])
 
 		// First node: catchAll node with empty path
 		child := &node{
@@ -302,27 +565,204 @@ func (n *node) insertChild(path, fullPath string, value interface{}) {
 
 		// Second node: node holding the variable
 		child = &node{
-			path:     path[i:],
+			path:     path[i : i+1+len(wildcard)],
 			nType:    catchAll,
-			value:    value,
 			priority: 1,
 		}
 		n.children = []*node{child}
+		n = child
+
+		// If the path doesn't end with the catch-all, the fixed suffix
+		// starting with '/' follows in another subpath
+		if i+1+len(wildcard) < len(path) {
+			path = path[i+1+len(wildcard):]
+			child := &node{
+				priority: 1,
+			}
+			n.children = []*node{child}
+			n = child
+			continue
+		}
 
+		n.value = value
 		return
 	}
 
 	// If no wildcard was found, simply insert the path and handle
-	n.path = path
+	n.path = unescapePath(path)
 	n.value = value
 }
 
+// walk calls fn for every node holding a value with the registered path of
+// the node, prefix is the path of all parent nodes. The nodes are visited in
+// lexical order of their paths. It stops and returns false as soon as fn
+// returns false.
+func (n *node) walk(prefix string, fn func(path string, n *node) bool) bool {
+	return n.visit(prefix, func(path string, n *node) WalkAction {
+		if n.value != nil && !fn(path, n) {
+			return Stop
+		}
+		return Continue
+	})
+}
+
+// visit calls fn for every node with the path of the node, prefix is the
+// path of all parent nodes. The nodes are visited in lexical order of their
+// paths, the children of a node are skipped if fn returns SkipSubtree.
+// It stops and returns false as soon as fn returns Stop.
+func (n *node) visit(prefix string, fn func(path string, n *node) WalkAction) bool {
+	prefix += n.routePath()
+	switch fn(prefix, n) {
+	case Stop:
+		return false
+	case SkipSubtree:
+		return true
+	}
+
+	children := n.children
+	if len(children) > 1 {
+		children = make([]*node, len(n.children))
+		copy(children, n.children)
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].path < children[j].path
+		})
+	}
+	for _, child := range children {
+		if !child.visit(prefix, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// findRoute returns the node holding the value registered with the given path.
+// In contrast to getValue, path is compared with the registered paths
+// literally, wildcards only match the same wildcards.
+// It returns nil if no value is registered for the path.
+func (n *node) findRoute(path string) *node {
+walk:
+	for {
+		if n.nType == param || n.nType == catchAll {
+			if len(path) < len(n.path) || path[:len(n.path)] != n.path {
+				return nil
+			}
+			path = path[len(n.path):]
+		} else {
+			i, j := commonPrefix(path, n.path)
+			if j < len(n.path) {
+				return nil
+			}
+			path = path[i:]
+		}
+		if path == "" {
+			if n.value == nil {
+				return nil
+			}
+			return n
+		}
+
+		// A param or catchAll node and a wildcard parent have a single child
+		if n.wildChild || ((n.nType == param || n.nType == catchAll) && len(n.children) == 1) {
+			n = n.children[0]
+			continue walk
+		}
+		idxc := firstByte(path)
+		for i, c := range []byte(n.indices) {
+			if c == idxc {
+				n = n.children[i]
+				continue walk
+			}
+		}
+		return nil
+	}
+}
+
+// remove returns a new tree holding all routes of n except the given path,
+// which must be the registered path, not a request path. Since the remaining
+// routes are added again, nodes which became empty are pruned.
+// The second return value reports whether the path was registered.
+func (n *node) remove(path string) (*node, bool) {
+	if n.findRoute(path) == nil {
+		return n, false
+	}
+	return n.rebuild(func(p string) bool { return p != path }), true
+}
+
+// rebuild returns a new tree holding the routes of n for which keep returns
+// true, all routes if keep is nil. The routes keep their metadata.
+func (n *node) rebuild(keep func(path string) bool) *node {
+	type route struct {
+		path  string
+		value interface{}
+		meta  *routeMeta
+	}
+
+	var routes []route
+	n.walk("", func(p string, n *node) bool {
+		if keep == nil || keep(p) {
+			routes = append(routes, route{p, n.value, n.meta})
+		}
+		return true
+	})
+
+	root := new(node)
+	for _, rt := range routes {
+		root.addRoute(rt.path, rt.value)
+		if rt.meta != nil {
+			root.findRoute(rt.path).meta = rt.meta
+		}
+	}
+	return root
+}
+
+// clone returns a deep copy of the tree n, the values are not copied.
+func (n *node) clone() *node {
+	c := *n
+	if n.meta != nil {
+		meta := *n.meta
+		c.meta = &meta
+	}
+	if n.children != nil {
+		c.children = make([]*node, len(n.children))
+		for i, child := range n.children {
+			c.children[i] = child.clone()
+		}
+	}
+	return &c
+}
+
+// empty reports whether the tree holds no routes.
+func (n *node) empty() bool {
+	return n.path == "" && n.indices == "" && n.value == nil
+}
+
 // Returns the value registered with the given path (key). The values of
 // wildcards are saved to a map.
 // If no value can be found, a TSR (trailing slash redirect) recommendation is
 // made if a value exists with an extra (without the) trailing slash for the
 // given path.
 func (n *node) getValue(path string, params func() *Params) (value interface{}, ps *Params, tsr bool) {
+	leaf, ps, tsr := n.lookup(path, params, nil)
+	if leaf != nil {
+		value = leaf.value
+	}
+	return value, ps, tsr
+}
+
+// lookup is getValue which returns the node holding the value and
+// additionally records the offsets of the wildcard values into view,
+// if it is not nil.
+func (n *node) lookup(path string, params func() *Params, view *ParamsView) (leaf *node, ps *Params, tsr bool) {
+	return n.lookupFrom(path, 0, params, view, nil)
+}
+
+// lookupFrom is lookup for a node below the root, the first skip bytes of
+// path were consumed by the nodes above n. opts, which may be nil, provide
+// the registered constraints and whether an empty last segment matches a
+// named parameter, see WithAllowEmptyParams.
+func (n *node) lookupFrom(path string, skip int, params func() *Params, view *ParamsView, opts *Options) (leaf *node, ps *Params, tsr bool) {
+	full, base := path, len(path)
+	path = path[skip:]
 walk: // Outer loop for walking the tree
 	for {
 		prefix := n.path
@@ -345,7 +785,7 @@ walk: // Outer loop for walking the tree
 					// Nothing found.
 					// We can recommend to redirect to the same URL without a
 					// trailing slash if a leaf exists for that path.
-					tsr = (path == "/" && n.value != nil)
+					tsr = (path == "/" && n.activeValue() != nil)
 					return
 				}
 
@@ -359,6 +799,20 @@ walk: // Outer loop for walking the tree
 						end++
 					}
 
+					name, suffix := splitSuffix(n.path[1:])
+					key, constraint := splitConstraint(name)
+					value := path[:end]
+					if suffix != "" {
+						// The literal suffix is required, the value is the stem
+						if len(value) <= len(suffix) || value[len(value)-len(suffix):] != suffix {
+							return
+						}
+						value = value[:len(value)-len(suffix)]
+					}
+					if constraint != "" && !opts.satisfies(constraint, value) {
+						return
+					}
+
 					// Save param value
 					if params != nil {
 						if ps == nil {
@@ -368,10 +822,14 @@ walk: // Outer loop for walking the tree
 						i := len(*ps)
 						*ps = (*ps)[:i+1]
 						(*ps)[i] = Param{
-							Key:   n.path[1:],
-							Value: path[:end],
+							Key:   key,
+							Value: value,
 						}
 					}
+					if view != nil {
+						start := base - len(path)
+						view.spans = append(view.spans, paramSpan{key, start, start + len(value)})
+					}
 
 					// We need to go deeper!
 					if end < len(path) {
@@ -386,13 +844,14 @@ walk: // Outer loop for walking the tree
 						return
 					}
 
-					if value = n.value; value != nil {
+					if n.activeValue() != nil {
+						leaf = n
 						return
 					} else if len(n.children) == 1 {
 						// No value found. Check if a value for this path + a
 						// trailing slash exists for TSR recommendation
 						n = n.children[0]
-						tsr = (n.path == "/" && n.value != nil) || (n.path == "" && n.indices == "/")
+						tsr = (n.path == "/" && n.activeValue() != nil) || (n.path == "" && n.indices == "/")
 					}
 
 					return
@@ -411,8 +870,20 @@ walk: // Outer loop for walking the tree
 							Value: path,
 						}
 					}
+					if view != nil {
+						view.spans = append(view.spans, paramSpan{n.path[2:], base - len(path), base})
+					}
 
-					value = n.value
+					// The catch-all followed by a fixed suffix, e.g.
+					// "/files/*path/meta.json", takes the most segments
+					if len(n.children) > 0 {
+						if sleaf, sps := n.lookupSuffix(full, base-len(path), ps, view, opts); sleaf != nil {
+							return sleaf, sps, false
+						}
+					}
+					if n.activeValue() != nil {
+						leaf = n
+					}
 					return
 
 				default:
@@ -422,7 +893,30 @@ walk: // Outer loop for walking the tree
 		} else if path == prefix {
 			// We should have reached the node containing the value.
 			// Check if this node has a value registered.
-			if value = n.value; value != nil {
+			if n.activeValue() != nil {
+				leaf = n
+				return
+			}
+
+			// The empty last segment is the value of the named parameter
+			if opts != nil && opts.allowEmptyParams && len(path) > 0 && path[len(path)-1] == '/' && n.wildChild &&
+				n.children[0].nType == param && n.children[0].activeValue() != nil {
+				n = n.children[0]
+				name, suffix := splitSuffix(n.path[1:])
+				key, constraint := splitConstraint(name)
+				if suffix != "" || constraint != "" && !opts.satisfies(constraint, "") {
+					return
+				}
+				if params != nil {
+					if ps == nil {
+						ps = params()
+					}
+					*ps = append(*ps, Param{Key: key})
+				}
+				if view != nil {
+					view.spans = append(view.spans, paramSpan{key, base, base})
+				}
+				leaf = n
 				return
 			}
 
@@ -439,8 +933,8 @@ walk: // Outer loop for walking the tree
 			for i, c := range []byte(n.indices) {
 				if c == '/' {
 					n = n.children[i]
-					tsr = (len(n.path) == 1 && n.value != nil) ||
-						(n.nType == catchAll && n.children[0].value != nil)
+					tsr = (len(n.path) == 1 && n.activeValue() != nil) ||
+						(n.nType == catchAll && n.children[0].activeValue() != nil)
 					return
 				}
 			}
@@ -451,16 +945,62 @@ walk: // Outer loop for walking the tree
 		// extra trailing slash if a leaf exists for that path
 		tsr = (path == "/") ||
 			(len(prefix) == len(path)+1 && prefix[len(path)] == '/' &&
-				path == prefix[:len(prefix)-1] && n.value != nil)
+				path == prefix[:len(prefix)-1] && n.activeValue() != nil)
 		return
 	}
 }
 
+// lookupSuffix looks up the rest of path after the catch-all node n, which
+// starts at offset start and has a fixed suffix. It tries the longest value
+// of the catch-all first and updates its param in ps and view, if the
+// suffix matches.
+func (n *node) lookupSuffix(path string, start int, ps *Params, view *ParamsView, opts *Options) (*node, *Params) {
+	var params func() *Params
+	var i, spans int
+	if ps != nil {
+		params = func() *Params { return ps }
+		i = len(*ps) - 1
+	}
+	if view != nil {
+		spans = len(view.spans)
+	}
+	// the catch-all takes at least one segment
+	for end := len(path); end > start+1; {
+		end = start + 1 + strings.LastIndexByte(path[start+1:end], '/')
+		if end <= start+1 {
+			break
+		}
+		if leaf, _, _ := n.children[0].lookupFrom(path, end, params, view, opts); leaf != nil {
+			if ps != nil {
+				(*ps)[i].Value = path[start:end]
+			}
+			if view != nil {
+				view.spans[spans-1].end = end
+			}
+			return leaf, ps
+		}
+		if ps != nil {
+			*ps = (*ps)[:i+1]
+		}
+		if view != nil {
+			view.spans = view.spans[:spans]
+		}
+	}
+	return nil, nil
+}
+
 // Makes a case-insensitive lookup of the given path and tries to find a value.
 // It can optionally also fix trailing slashes.
 // It returns the case-corrected path and a bool indicating whether the lookup
 // was successful.
 func (n *node) findCaseInsensitivePath(path string, fixTrailingSlash bool) (fixedPath string, found bool) {
+	return n.findCaseInsensitivePathFor(path, fixTrailingSlash, nil)
+}
+
+// findCaseInsensitivePathFor is findCaseInsensitivePath which checks the
+// suffixes and constraints of the named parameters like lookupFrom, opts
+// may be nil.
+func (n *node) findCaseInsensitivePathFor(path string, fixTrailingSlash bool, opts *Options) (fixedPath string, found bool) {
 	const stackBufSize = 128
 
 	// Use a static sized buffer on the stack in the common case.
@@ -475,6 +1015,7 @@ func (n *node) findCaseInsensitivePath(path string, fixTrailingSlash bool) (fixe
 		buf,       // Preallocate enough memory for new path
 		[4]byte{}, // Empty rune buffer
 		fixTrailingSlash,
+		opts,
 	)
 
 	return string(ciPath), ciPath != nil
@@ -497,7 +1038,7 @@ func shiftNRuneBytes(rb [4]byte, n int) [4]byte {
 }
 
 // Recursive case-insensitive lookup function used by n.findCaseInsensitivePath
-func (n *node) findCaseInsensitivePathRec(path string, ciPath []byte, rb [4]byte, fixTrailingSlash bool) []byte {
+func (n *node) findCaseInsensitivePathRec(path string, ciPath []byte, rb [4]byte, fixTrailingSlash bool, opts *Options) []byte {
 	npLen := len(n.path)
 
 walk: // Outer loop for walking the tree
@@ -557,7 +1098,7 @@ walk: // Outer loop for walking the tree
 							// uppercase byte and the lowercase byte might exist
 							// as an index
 							if out := n.children[i].findCaseInsensitivePathRec(
-								path, ciPath, rb, fixTrailingSlash,
+								path, ciPath, rb, fixTrailingSlash, opts,
 							); out != nil {
 								return out
 							}
@@ -586,7 +1127,7 @@ walk: // Outer loop for walking the tree
 
 				// Nothing found. We can recommend to redirect to the same URL
 				// without a trailing slash if a leaf exists for that path
-				if fixTrailingSlash && path == "/" && n.value != nil {
+				if fixTrailingSlash && path == "/" && n.activeValue() != nil {
 					return ciPath
 				}
 				return nil
@@ -601,8 +1142,23 @@ walk: // Outer loop for walking the tree
 					end++
 				}
 
+				name, suffix := splitSuffix(n.path[1:])
+				_, constraint := splitConstraint(name)
+				value := path[:end]
+				if suffix != "" {
+					// The literal suffix is required, its case is fixed too
+					if len(value) <= len(suffix) || !strings.EqualFold(value[len(value)-len(suffix):], suffix) {
+						return nil
+					}
+					value = value[:len(value)-len(suffix)]
+				}
+				if constraint != "" && !opts.satisfies(constraint, value) {
+					return nil
+				}
+
 				// Add param value to case insensitive path
-				ciPath = append(ciPath, path[:end]...)
+				ciPath = append(ciPath, value...)
+				ciPath = append(ciPath, suffix...)
 
 				// We need to go deeper!
 				if end < len(path) {
@@ -621,19 +1177,22 @@ walk: // Outer loop for walking the tree
 					return nil
 				}
 
-				if n.value != nil {
+				if n.activeValue() != nil {
 					return ciPath
 				} else if fixTrailingSlash && len(n.children) == 1 {
 					// No handle found. Check if a handle for this path + a
 					// trailing slash exists
 					n = n.children[0]
-					if n.path == "/" && n.value != nil {
+					if n.path == "/" && n.activeValue() != nil {
 						return append(ciPath, '/')
 					}
 				}
 				return nil
 
 			case catchAll:
+				if n.activeValue() == nil {
+					return nil
+				}
 				return append(ciPath, path...)
 
 			default:
@@ -642,7 +1201,7 @@ walk: // Outer loop for walking the tree
 		} else {
 			// We should have reached the node containing the handle.
 			// Check if this node has a handle registered.
-			if n.value != nil {
+			if n.activeValue() != nil {
 				return ciPath
 			}
 
@@ -652,8 +1211,8 @@ walk: // Outer loop for walking the tree
 				for i, c := range []byte(n.indices) {
 					if c == '/' {
 						n = n.children[i]
-						if (len(n.path) == 1 && n.value != nil) ||
-							(n.nType == catchAll && n.children[0].value != nil) {
+						if (len(n.path) == 1 && n.activeValue() != nil) ||
+							(n.nType == catchAll && n.children[0].activeValue() != nil) {
 							return append(ciPath, '/')
 						}
 						return nil
@@ -671,7 +1230,7 @@ walk: // Outer loop for walking the tree
 			return ciPath
 		}
 		if len(path)+1 == npLen && n.path[len(path)] == '/' &&
-			strings.EqualFold(path[1:], n.path[1:len(path)]) && n.value != nil {
+			strings.EqualFold(path[1:], n.path[1:len(path)]) && n.activeValue() != nil {
 			return append(ciPath, n.path...)
 		}
 	}
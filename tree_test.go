@@ -91,6 +91,9 @@ func TestCountParams(t *testing.T) {
 	if countParams(strings.Repeat("/:param", 256)) != 256 {
 		t.Fail()
 	}
+	if countParams(`/a\:b/:c/\*d`) != 1 {
+		t.Fail()
+	}
 }
 
 func TestTreeAddAndGet(t *testing.T) {
@@ -310,15 +313,94 @@ func TestEmptyWildcardName(t *testing.T) {
 
 func TestTreeCatchAllConflict(t *testing.T) {
 	routes := []testRoute{
-		{"/src/*filepath/x", true},
+		{"/src/*filepath/x", false},
+		{"/src/*filepath/y/*rest", true},
 		{"/src2/", false},
 		{"/src2/*filepath/x", true},
 		{"/src3/*filepath", false},
-		{"/src3/*filepath/x", true},
+		{"/src3/*filepath/x", false},
+		{"/src3/*path/y", true},
 	}
 	testRoutes(t, routes)
 }
 
+func TestTreeCatchAllSuffix(t *testing.T) {
+	tree := &node{}
+
+	routes := [...]string{
+		"/files/*path/meta.json",
+		"/files/*path",
+		"/files/*path/data/:id",
+		"/src/*path/",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, route)
+	}
+
+	checkRequests(t, tree, testRequests{
+		{"/files/a/meta.json", false, "/files/*path/meta.json", Params{Param{"path", "/a"}}},
+		{"/files/a/b/meta.json", false, "/files/*path/meta.json", Params{Param{"path", "/a/b"}}},
+		{"/files/a/meta.json/meta.json", false, "/files/*path/meta.json", Params{Param{"path", "/a/meta.json"}}},
+		{"/files/meta.json", false, "/files/*path", Params{Param{"path", "/meta.json"}}},
+		{"/files/a/b/data/1", false, "/files/*path/data/:id", Params{Param{"path", "/a/b"}, Param{"id", "1"}}},
+		{"/files/a/data/", false, "/files/*path", Params{Param{"path", "/a/data/"}}},
+		{"/src/a/b/", false, "/src/*path/", Params{Param{"path", "/a/b"}}},
+		{"/src/a", true, "", Params{Param{"path", "/a"}}},
+	})
+
+	checkPriorities(t, tree)
+
+	for _, route := range routes {
+		if n := tree.findRoute(route); n == nil || n.value != route {
+			t.Errorf("route '%s' not found", route)
+		}
+	}
+}
+
+func TestTreeEscapes(t *testing.T) {
+	tree := &node{}
+
+	routes := [...]string{
+		`/a\:b/c`,
+		`/a\:c`,
+		`/files/\*`,
+		`/files/x`,
+		`/v1/\:undelete/:id`,
+	}
+	for _, route := range routes {
+		tree.addRoute(route, route)
+	}
+
+	checkRequests(t, tree, testRequests{
+		{"/a:b/c", false, `/a\:b/c`, nil},
+		{"/a:c", false, `/a\:c`, nil},
+		{"/a:d", true, "", nil},
+		{"/files/*", false, `/files/\*`, nil},
+		{"/files/x", false, "/files/x", nil},
+		{"/v1/:undelete/7", false, `/v1/\:undelete/:id`, Params{Param{"id", "7"}}},
+	})
+
+	checkPriorities(t, tree)
+
+	var walked []string
+	tree.walk("", func(path string, _ *node) bool {
+		walked = append(walked, path)
+		return true
+	})
+	for _, route := range routes {
+		if n := tree.findRoute(route); n == nil || n.value != route {
+			t.Errorf("route '%s' not found", route)
+		}
+	}
+	if want := []string{`/a\:b/c`, `/a\:c`, `/files/\*`, "/files/x", `/v1/\:undelete/:id`}; !reflect.DeepEqual(walked, want) {
+		t.Errorf("walked %v, want %v", walked, want)
+	}
+
+	if recv := catchPanic(func() { tree.addRoute("/a:x", "/a:x") }); recv == nil {
+		t.Error("no panic for a wildcard conflicting with an escaped ':'")
+	}
+}
+
 func TestTreeCatchAllConflictRoot(t *testing.T) {
 	t.Run("catch all conflict root", func(t *testing.T) {
 		routes := []testRoute{
@@ -695,3 +777,188 @@ func TestTreeWildcardConflictEx(t *testing.T) {
 		}
 	}
 }
+
+func TestTreeConflictError(t *testing.T) {
+	conflicts := []struct {
+		routes   []string
+		route    string
+		existing string
+		segment  int
+	}{
+		{[]string{"/user_:name/about"}, "/user_:id", "/user_:name/about", 0},
+		{[]string{"/cmd/:tool/:sub"}, "/cmd/:tool/:other/x", "/cmd/:tool/:sub", 2},
+		{[]string{"/src/*filepath"}, "/src/*other", "/src/*filepath", 1},
+		{[]string{"/search/", "/search/go"}, "/search/:query", "/search/go", 1},
+	}
+	for _, conflict := range conflicts {
+		tree := &node{}
+		for _, route := range conflict.routes {
+			tree.addRoute(route, route)
+		}
+
+		recv := catchPanic(func() {
+			tree.addRoute(conflict.route, conflict.route)
+		})
+		err, ok := recv.(*ConflictError)
+		if !ok {
+			t.Fatalf("expected ConflictError for route '%s', got %v", conflict.route, recv)
+		}
+		if err.Path != conflict.route || err.Existing != conflict.existing || err.Segment != conflict.segment {
+			t.Errorf("ConflictError for route '%s' = {%q %q %d}, want {%q %q %d}", conflict.route,
+				err.Path, err.Existing, err.Segment, conflict.route, conflict.existing, conflict.segment)
+		}
+	}
+}
+
+func TestTreeRemove(t *testing.T) {
+	tree := &node{}
+
+	routes := [...]string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/cmd/:tool/",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/doc/",
+		"/doc/go_faq.html",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, route)
+	}
+
+	tree, removed := tree.remove("/cmd/:tool/:sub")
+	if !removed {
+		t.Fatal("route '/cmd/:tool/:sub' not removed")
+	}
+	tree, removed = tree.remove("/doc/go_faq.html")
+	if !removed {
+		t.Fatal("route '/doc/go_faq.html' not removed")
+	}
+	if _, removed = tree.remove("/cmd/test/3"); removed {
+		t.Fatal("request path must not remove a route")
+	}
+
+	checkRequests(t, tree, testRequests{
+		{"/", false, "/", nil},
+		{"/cmd/test/", false, "/cmd/:tool/", Params{Param{"tool", "test"}}},
+		{"/cmd/test/3", true, "", Params{Param{"tool", "test"}}},
+		{"/src/some/file.png", false, "/src/*filepath", Params{Param{"filepath", "/some/file.png"}}},
+		{"/search/someth!ng", false, "/search/:query", Params{Param{"query", "someth!ng"}}},
+		{"/user_gopher/about", false, "/user_:name/about", Params{Param{"name", "gopher"}}},
+		{"/doc/", false, "/doc/", nil},
+		{"/doc/go_faq.html", true, "", nil},
+	})
+
+	checkPriorities(t, tree)
+}
+
+func TestTreeFindRoute(t *testing.T) {
+	tree := &node{}
+
+	routes := [...]string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/cmd/:tool/",
+		"/src/*filepath",
+		"/search/",
+		"/user_:name",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, route)
+	}
+
+	for _, route := range routes {
+		n := tree.findRoute(route)
+		if n == nil || n.value != route {
+			t.Errorf("route '%s' not found", route)
+		}
+	}
+	for _, path := range [...]string{"/cmd/test/", "/cmd/:tool", "/cmd/:tool/:sub/", "/src/", "/sea", "/user_gopher", "/nope"} {
+		if n := tree.findRoute(path); n != nil {
+			t.Errorf("unexpected route found for '%s'", path)
+		}
+	}
+}
+
+func TestTreeEmptyParams(t *testing.T) {
+	opts := &Options{allowEmptyParams: true}
+	tree := &node{}
+	routes := [...]string{
+		"/users/:id",
+		"/users/:id/posts",
+		"/files/:dir/",
+		"/static/",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, route)
+	}
+
+	tests := []struct {
+		path  string
+		route string
+		ps    Params
+	}{
+		{"/users/", "/users/:id", Params{{"id", ""}}},
+		{"/users//posts", "/users/:id/posts", Params{{"id", ""}}},
+		{"/files//", "/files/:dir/", Params{{"dir", ""}}},
+		{"/static/", "/static/", nil},
+	}
+	for _, tt := range tests {
+		getParams := func() *Params {
+			ps := make(Params, 0, 1)
+			return &ps
+		}
+		leaf, ps, _ := tree.lookupFrom(tt.path, 0, getParams, nil, opts)
+		if leaf == nil {
+			t.Errorf("value mismatch for route '%s': Expected non-nil value", tt.path)
+			continue
+		}
+		if leaf.value.(string) != tt.route {
+			t.Errorf("value mismatch for route '%s': Wrong value (%s != %s)", tt.path, leaf.value, tt.route)
+		}
+		var params Params
+		if ps != nil {
+			params = *ps
+		}
+		if !reflect.DeepEqual(params, tt.ps) {
+			t.Errorf("Params mismatch for route '%s'", tt.path)
+		}
+	}
+
+	if leaf, _, _ := tree.lookup("/users/", nil, nil); leaf != nil {
+		t.Errorf("expected no value for route '/users/' without empty params")
+	}
+	if leaf, _, _ := tree.lookupFrom("/files/", 0, nil, nil, opts); leaf != nil {
+		t.Errorf("expected no value for route '/files/'")
+	}
+
+	tree = &node{}
+	tree.addRoute("/:root", "/:root")
+	if leaf, _, _ := tree.lookupFrom("/", 0, nil, nil, opts); leaf == nil {
+		t.Errorf("expected value for route '/'")
+	}
+}
+
+func TestTreeDuplicateParamNames(t *testing.T) {
+	routes := [...]string{
+		"/a/:id/b/:id",
+		"/a/:id/*id",
+		"/:x/:y/:x/",
+	}
+	for _, route := range routes {
+		recv := catchPanic(func() {
+			tree := &node{}
+			tree.addRoute(route, route)
+		})
+		if rs, ok := recv.(string); !ok || !strings.Contains(rs, "used more than once") {
+			t.Errorf("no panic for duplicate wildcard name in route '%s': %v", route, recv)
+		}
+	}
+
+	tree := &node{}
+	tree.addRoute("/a/:id/b/:name", "ok")
+	tree.addRoute("/c/:id", "ok")
+}
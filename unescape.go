@@ -0,0 +1,41 @@
+package wrmatch
+
+import (
+	"net/url"
+	"strings"
+)
+
+// WithUnescapeParams percent-decodes the values of the named parameters and
+// catch-alls, e.g. /hello/John%20Doe matches /hello/:name with the name
+// "John Doe". The routes are still matched against the raw path, so an
+// escaped slash never separates segments: in a named parameter %2F is
+// decoded to '/', in a catch-all it is kept escaped, so that the segments
+// of the decoded value are the segments of the path. Values which are not
+// validly escaped are returned as is.
+// The values of ParamsView, see Router.MatchView, are not decoded.
+// Default: disable
+func WithUnescapeParams() Option {
+	return func(r *Options) {
+		r.unescapeParams = true
+	}
+}
+
+// escapedSlash replaces an escaped slash by its escaped escape sequence, so
+// that it stays escaped when unescaped.
+var escapedSlash = strings.NewReplacer("%2F", "%252F", "%2f", "%252f")
+
+// unescapeParam percent-decodes the param value, escaped slashes are kept in
+// catch-all values.
+func unescapeParam(value string, catchAll bool) string {
+	if strings.IndexByte(value, '%') < 0 {
+		return value
+	}
+	escaped := value
+	if catchAll {
+		escaped = escapedSlash.Replace(value)
+	}
+	if unescaped, err := url.PathUnescape(escaped); err == nil {
+		return unescaped
+	}
+	return value
+}
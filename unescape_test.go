@@ -0,0 +1,43 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnescapeParams(t *testing.T) {
+	router := New(WithUnescapeParams())
+	router.GET("/hello/:name", "hello")
+	router.GET("/files/*path", "files")
+
+	tests := []struct {
+		path  string
+		key   string
+		value string
+	}{
+		{"/hello/John%20Doe", "name", "John Doe"},
+		{"/hello/a%2Fb", "name", "a/b"},
+		{"/hello/100%", "name", "100%"},
+		{"/hello/plain", "name", "plain"},
+		{"/files/a%20b/c%2Fd", "path", "/a b/c%2Fd"},
+		{"/files/a%2fb", "path", "/a%2fb"},
+	}
+	for _, tt := range tests {
+		_, ps, matched := router.Match(http.MethodGet, tt.path)
+		require.True(t, matched, tt.path)
+		require.Equal(t, tt.value, ps.Param(tt.key), tt.path)
+	}
+
+	_, ps, _ := New().GET("/hello/:name", "hello").Match(http.MethodGet, "/hello/John%20Doe")
+	require.Equal(t, "John%20Doe", ps.Param("name"))
+
+	pattern := NewPattern(WithUnescapeParams())
+	pattern.Add("/hello/:name", "hello")
+	_, ps, matched := pattern.Match("/hello/John%20Doe")
+	require.True(t, matched)
+	require.Equal(t, "John Doe", ps.Param("name"))
+	_, ps, _ = pattern.Lookup("/hello/John%20Doe")
+	require.Equal(t, "John Doe", ps.Param("name"))
+}
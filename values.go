@@ -0,0 +1,27 @@
+package wrmatch
+
+// WithAppendValues enables the append mode of Router.Add: adding a value for
+// a method and path which is registered already appends the value to the
+// route instead of panicking, e.g. to attach several policies to the same
+// path. Match returns the value added first, MatchValues all values.
+// Default: disable
+func WithAppendValues() Option {
+	return func(r *Options) {
+		r.appendValues = true
+	}
+}
+
+// MatchValues is like Match, but returns all values of the matched route in
+// the order they were added, see WithAppendValues. The value resolver only
+// applies to the first value.
+func (r *Router) MatchValues(method, path string) ([]interface{}, Params, bool) {
+	leaf, value, ps := r.match(method, path, r.paramsNew, nil)
+	if leaf == nil {
+		return nil, nil, false
+	}
+	values := []interface{}{value}
+	if leaf.meta != nil {
+		values = append(values, leaf.meta.values...)
+	}
+	return values, ps, true
+}
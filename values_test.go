@@ -0,0 +1,42 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterMatchValues(t *testing.T) {
+	router := New(WithAppendValues())
+	router.GET("/user/:name", "auth")
+	router.GET("/user/:name", "quota")
+	router.GET("/user/:name", "logging")
+	router.GET("/team", "team")
+
+	values, ps, matched := router.MatchValues(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, []interface{}{"auth", "quota", "logging"}, values)
+	require.Equal(t, Params{Param{"name", "gopher"}}, ps)
+	v, _, matched := router.Match(http.MethodGet, "/user/gopher")
+	require.True(t, matched)
+	require.Equal(t, "auth", v)
+
+	values, _, matched = router.MatchValues(http.MethodGet, "/team")
+	require.True(t, matched)
+	require.Equal(t, []interface{}{"team"}, values)
+	values, _, matched = router.MatchValues(http.MethodGet, "/missing")
+	require.False(t, matched)
+	require.Nil(t, values)
+
+	// clones do not share appended values
+	c := router.Clone()
+	c.GET("/user/:name", "clone")
+	values, _, _ = router.MatchValues(http.MethodGet, "/user/gopher")
+	require.Len(t, values, 3)
+	values, _, _ = c.MatchValues(http.MethodGet, "/user/gopher")
+	require.Len(t, values, 4)
+
+	// without the append mode duplicates panic
+	require.Panics(t, func() { New().GET("/", "a").GET("/", "b") })
+}
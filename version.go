@@ -0,0 +1,68 @@
+package wrmatch
+
+// VersionParam is the Param name under which the version of a route mounted
+// with Router.MountVersions is stored.
+var VersionParam = "$version"
+
+// MountVersions registers all routes of sub under prefix + "/" + version for
+// every given version, like Mount. The version is added to the url params of
+// a match under VersionParam, e.g.
+//
+//  router.MountVersions("/api", sub, "v1", "v2")
+//
+// registers the route "/users/:id" of sub as "/api/v1/users/:id" and
+// "/api/v2/users/:id". The versions can be disabled and enabled again with
+// DisableVersion and EnableVersion.
+func (r *Router) MountVersions(prefix string, sub *Router, versions ...string) *Router {
+	if len(versions) == 0 {
+		panic("versions must not be empty in prefix '" + prefix + "'")
+	}
+	for _, version := range versions {
+		if version == "" {
+			panic("version must not be empty in prefix '" + prefix + "'")
+		}
+		vprefix := prefix
+		if len(vprefix) == 0 || vprefix[len(vprefix)-1] != '/' {
+			vprefix += "/"
+		}
+		vprefix += version
+		r.Mount(vprefix, sub)
+
+		for _, method := range sub.methods() {
+			sub.trees[method].walk("", func(path string, _ *node) bool {
+				meta := r.findRoute(method, vprefix+path).routeMeta()
+				meta.params = append(meta.params[:len(meta.params):len(meta.params)], Param{VersionParam, version})
+				return true
+			})
+		}
+	}
+	r.updateMaxParams()
+	return r
+}
+
+// DisableVersion disables all routes mounted with MountVersions for the
+// given version. It returns the number of routes which were disabled.
+func (r *Router) DisableVersion(version string) int {
+	return r.setVersionDisabled(version, true)
+}
+
+// EnableVersion enables all routes mounted with MountVersions for the
+// given version again. It returns the number of routes which were enabled.
+func (r *Router) EnableVersion(version string) int {
+	return r.setVersionDisabled(version, false)
+}
+
+func (r *Router) setVersionDisabled(version string, disabled bool) int {
+	r.checkSealed()
+	count := 0
+	for _, root := range r.trees {
+		root.walk("", func(_ string, n *node) bool {
+			if n.meta != nil && n.meta.disabled != disabled && n.meta.params.Param(VersionParam) == version {
+				n.meta.disabled = disabled
+				count++
+			}
+			return true
+		})
+	}
+	return count
+}
@@ -0,0 +1,40 @@
+package wrmatch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterMountVersions(t *testing.T) {
+	sub := New()
+	sub.GET("/users/:id", "user")
+	sub.GET("/health", "health")
+
+	router := New()
+	router.MountVersions("/api", sub, "v1", "v2")
+
+	v, ps, matched := router.Match(http.MethodGet, "/api/v2/users/42")
+	require.True(t, matched)
+	require.Equal(t, "user", v)
+	require.Equal(t, Params{Param{"id", "42"}, Param{VersionParam, "v2"}}, ps)
+
+	_, view, matched := router.MatchView(http.MethodGet, "/api/v1/health")
+	require.True(t, matched)
+	require.Equal(t, "v1", view.Param(VersionParam))
+	require.Equal(t, Params{Param{VersionParam, "v1"}}, view.Params())
+
+	require.Equal(t, 2, router.DisableVersion("v1"))
+	_, _, matched = router.Match(http.MethodGet, "/api/v1/users/42")
+	require.False(t, matched)
+	_, _, matched = router.Match(http.MethodGet, "/api/v2/users/42")
+	require.True(t, matched)
+	require.Equal(t, 0, router.DisableVersion("v1"))
+	require.Equal(t, 2, router.EnableVersion("v1"))
+	_, _, matched = router.Match(http.MethodGet, "/api/v1/users/42")
+	require.True(t, matched)
+
+	require.Panics(t, func() { New().MountVersions("/api", sub) })
+	require.Panics(t, func() { New().MountVersions("/api", sub, "") })
+}
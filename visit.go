@@ -0,0 +1,34 @@
+package wrmatch
+
+// WalkAction tells Router.Visit how to continue the traversal.
+type WalkAction int
+
+const (
+	// Continue visits the children of the node.
+	Continue WalkAction = iota
+	// SkipSubtree skips the children of the node.
+	SkipSubtree
+	// Stop ends the traversal.
+	Stop
+)
+
+// Visit calls fn for every node of the trees, by method in lexical order,
+// with the path of the node including its parents, e.g. "/user/" or
+// "/user/:name". route describes the route of the node, it is nil for the
+// nodes which only hold a common prefix. The nodes are visited in lexical
+// order of their paths, parents before their children, so expensive
+// analyses can prune the traversal with SkipSubtree or end it with Stop.
+// Overlays are not visited.
+func (r *Router) Visit(fn func(method, path string, route *Route) WalkAction) {
+	for _, method := range r.methods() {
+		if !r.trees[method].visit("", func(path string, n *node) WalkAction {
+			if n.value == nil {
+				return fn(method, path, nil)
+			}
+			route := newRoute(method, path, n)
+			return fn(method, path, &route)
+		}) {
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package wrmatch
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouterVisit(t *testing.T) {
+	router := New()
+	router.GET("/user/:name", "user")
+	router.GET("/user/:name/posts", "posts")
+	router.GET("/team", "team")
+	router.POST("/user", "create")
+
+	var routes []string
+	router.Visit(func(method, path string, route *Route) WalkAction {
+		if route != nil {
+			require.Equal(t, method, route.Method)
+			require.Equal(t, path, route.Path)
+			routes = append(routes, method+" "+path)
+		}
+		return Continue
+	})
+	require.Equal(t, []string{
+		"GET /team",
+		"GET /user/:name",
+		"GET /user/:name/posts",
+		"POST /user",
+	}, routes)
+
+	// skip the routes below /user/
+	routes = nil
+	router.Visit(func(method, path string, route *Route) WalkAction {
+		if strings.HasPrefix(path, "/user/") {
+			return SkipSubtree
+		}
+		if route != nil {
+			routes = append(routes, method+" "+path)
+		}
+		return Continue
+	})
+	require.Equal(t, []string{"GET /team", "POST /user"}, routes)
+
+	// stop at the first route
+	routes = nil
+	router.Visit(func(method, path string, route *Route) WalkAction {
+		if route != nil {
+			routes = append(routes, method+" "+path)
+			return Stop
+		}
+		return Continue
+	})
+	require.Equal(t, []string{http.MethodGet + " /team"}, routes)
+}